@@ -0,0 +1,86 @@
+// Package cache persists each panel's last successful payload so the
+// dashboard can show something immediately on startup, even offline.
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type entry struct {
+	Saved time.Time       `json:"saved"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Store reads and writes one JSON file per panel in Dir. The zero Store is
+// disabled: loads miss and saves do nothing.
+type Store struct {
+	Dir string
+}
+
+// DefaultDir returns $XDG_CACHE_HOME/pulse, falling back to ~/.cache.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".cache")
+	}
+	return filepath.Join(dir, "pulse")
+}
+
+func (s Store) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+// Load returns the payload cached for id and when it was saved.
+func (s Store) Load(id string) ([]byte, time.Time, error) {
+	if s.Dir == "" {
+		return nil, time.Time{}, os.ErrNotExist
+	}
+	raw, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		return nil, time.Time{}, err
+	}
+	return e.Data, e.Saved, nil
+}
+
+// Save writes v as id's payload, stamped with the current time. The file is
+// replaced atomically so a crash never leaves a half-written cache.
+func (s Store) Save(id string, v any) error {
+	if s.Dir == "" {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(entry{Saved: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(id))
+}
@@ -1,35 +1,176 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+	"pulse/internal/layout"
+	"pulse/internal/retry"
+	"pulse/internal/style"
 )
 
+// Config holds the global settings plus the list of panels to show. The
+// top-level fields act as defaults for every panel instance; environment
+// variables override them so .env setups keep working alongside a file.
 type Config struct {
-	WeatherAPIKey string
-	WeatherCity   string
-	GitHubUser    string
-	GitHubToken   string
-	CryptoCoins   []string
+	WeatherAPIKey string             `yaml:"weather_api_key"`
+	WeatherCity   string             `yaml:"weather_city"`
+	GitHubUser    string             `yaml:"github_username"`
+	GitHubToken   string             `yaml:"github_token"`
+	CryptoCoins   []string           `yaml:"crypto_coins"`
+	CryptoQuotes  []string           `yaml:"crypto_currencies"` // vs currencies, first is primary
+	HTTP          HTTPConfig         `yaml:"http"`
+	BaseURLs      BaseURLs           `yaml:"base_urls"`
+	CacheDir      string             `yaml:"cache_dir"`
+	Panels        []PanelConfig      `yaml:"panels"`
+	Layout        *layout.Node       `yaml:"layout"` // nil: automatic grid
+	Theme         style.Theme        `yaml:"theme"`
+	Keys          map[string]KeyList `yaml:"keys"` // action → keys
+	Mouse         bool               `yaml:"mouse"`
+
+	// HTTPClient is built from HTTP by Load. Tests and embedders can set it
+	// directly, e.g. to an httptest.Server's client.
+	HTTPClient *http.Client `yaml:"-"`
+}
+
+// PanelConfig declares one panel instance. Options beyond the common fields
+// are specific to the panel type and read with Decode.
+type PanelConfig struct {
+	Type    string        `yaml:"type"`
+	ID      string        `yaml:"id"`
+	Title   string        `yaml:"title"`
+	Key     string        `yaml:"key"`
+	Refresh time.Duration `yaml:"refresh"`
+	Retry   retry.Policy  `yaml:"retry"`
+
+	options yaml.Node
+}
+
+func (p *PanelConfig) UnmarshalYAML(node *yaml.Node) error {
+	type plain PanelConfig
+	if err := node.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.options = *node
+	return nil
+}
+
+// Decode reads the panel's type-specific options into v. Fields missing
+// from the file are left untouched, so v can be pre-filled with defaults.
+func (p PanelConfig) Decode(v any) error {
+	if p.options.Kind == 0 {
+		return nil
+	}
+	if err := p.options.Decode(v); err != nil {
+		return fmt.Errorf("panel %q: %w", p.Type, err)
+	}
+	return nil
+}
+
+// KeyList is the keys bound to one action. In the config file it is a
+// single key or a list.
+type KeyList []string
+
+func (k *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = KeyList{node.Value}
+		return nil
+	}
+	return node.Decode((*[]string)(k))
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/pulse/config.yaml, falling back to
+// ~/.config when XDG_CONFIG_HOME is unset.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "pulse", "config.yaml")
 }
 
-func Load() Config {
+// Load reads the config file at path, then applies .env and environment
+// overrides. An empty path means DefaultPath, which may be absent.
+func Load(path string) (Config, error) {
 	godotenv.Load()
 
-	coins := strings.Split(os.Getenv("CRYPTO_COINS"), ",")
-	if len(coins) == 1 && coins[0] == "" {
-		coins = []string{"bitcoin", "ethereum", "solana"}
+	cfg := Config{
+		WeatherCity:  "Istanbul",
+		GitHubUser:   "TRINITY-21",
+		CryptoCoins:  []string{"bitcoin", "ethereum", "solana"},
+		CryptoQuotes: []string{"usd"},
+		HTTP: HTTPConfig{
+			Timeout:   15 * time.Second,
+			UserAgent: "pulse",
+		},
+		BaseURLs: defaultBaseURLs,
+		Mouse:    true,
+	}
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return cfg, fmt.Errorf("parse %s: %w", path, err)
+			}
+		case explicit || !errors.Is(err, fs.ErrNotExist):
+			return cfg, err
+		}
+	}
+
+	if v := os.Getenv("OPENWEATHER_API_KEY"); v != "" {
+		cfg.WeatherAPIKey = v
+	}
+	cfg.WeatherCity = envDefault("WEATHER_CITY", cfg.WeatherCity)
+	cfg.GitHubUser = envDefault("GITHUB_USERNAME", cfg.GitHubUser)
+	cfg.GitHubToken = envDefault("GITHUB_TOKEN", cfg.GitHubToken)
+	if v := os.Getenv("CRYPTO_COINS"); v != "" {
+		cfg.CryptoCoins = strings.Split(v, ",")
+	}
+	if v := os.Getenv("CRYPTO_CURRENCIES"); v != "" {
+		cfg.CryptoQuotes = strings.Split(v, ",")
+	}
+
+	if cfg.Layout != nil {
+		if err := cfg.Layout.Validate(); err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	theme, err := style.Resolve(cfg.Theme)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.Theme = theme
+
+	for _, u := range []*string{&cfg.BaseURLs.OpenWeather, &cfg.BaseURLs.CoinGecko, &cfg.BaseURLs.HackerNews, &cfg.BaseURLs.GitHub} {
+		*u = strings.TrimSuffix(*u, "/")
 	}
 
-	return Config{
-		WeatherAPIKey: os.Getenv("OPENWEATHER_API_KEY"),
-		WeatherCity:   envDefault("WEATHER_CITY", "Istanbul"),
-		GitHubUser:    envDefault("GITHUB_USERNAME", "TRINITY-21"),
-		GitHubToken:   os.Getenv("GITHUB_TOKEN"),
-		CryptoCoins:   coins,
+	client, err := cfg.HTTP.NewClient()
+	if err != nil {
+		return cfg, err
 	}
+	cfg.HTTPClient = client
+
+	return cfg, nil
 }
 
 func envDefault(key, fallback string) string {
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPConfig controls the client shared by every fetcher. When Proxy is
+// empty the standard HTTP_PROXY/HTTPS_PROXY variables apply.
+type HTTPConfig struct {
+	Timeout   time.Duration `yaml:"timeout"`
+	UserAgent string        `yaml:"user_agent"`
+	Proxy     string        `yaml:"proxy"`
+	CAFile    string        `yaml:"ca_file"`
+}
+
+// BaseURLs points each provider at its API root. Override them to use a
+// mirror, a corporate gateway or a local test server.
+type BaseURLs struct {
+	OpenWeather string `yaml:"openweather"`
+	CoinGecko   string `yaml:"coingecko"`
+	HackerNews  string `yaml:"hackernews"`
+	GitHub      string `yaml:"github"`
+}
+
+var defaultBaseURLs = BaseURLs{
+	OpenWeather: "https://api.openweathermap.org",
+	CoinGecko:   "https://api.coingecko.com/api/v3",
+	HackerNews:  "https://hacker-news.firebaseio.com/v0",
+	GitHub:      "https://api.github.com",
+}
+
+// NewClient builds an http.Client from the settings.
+func (h HTTPConfig) NewClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if h.Proxy != "" {
+		u, err := url.Parse(h.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("http proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+
+	if h.CAFile != "" {
+		pem, err := os.ReadFile(h.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("http ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("http ca_file %s: no certificates found", h.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{
+		Timeout:   h.Timeout,
+		Transport: userAgentTransport{agent: h.UserAgent, next: transport},
+	}, nil
+}
+
+type userAgentTransport struct {
+	agent string
+	next  http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.agent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.agent)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Client returns the shared HTTP client, or http.DefaultClient for a Config
+// that was built by hand rather than through Load.
+func (c Config) Client() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewClientUserAgent(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("User-Agent"))
+	}))
+	defer srv.Close()
+
+	client, err := HTTPConfig{Timeout: time.Second, UserAgent: "pulse-test"}.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.Timeout != time.Second {
+		t.Errorf("Timeout = %v, want 1s", client.Timeout)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// A fetcher's own User-Agent wins.
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("User-Agent", "custom")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(got) != 2 || got[0] != "pulse-test" || got[1] != "custom" {
+		t.Errorf("User-Agent = %q, want pulse-test then custom", got)
+	}
+}
+
+func TestNewClientErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		http HTTPConfig
+	}{
+		{"bad proxy", HTTPConfig{Proxy: "://nope"}},
+		{"missing ca file", HTTPConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"ca file without certificates", HTTPConfig{CAFile: empty}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.http.NewClient(); err == nil {
+				t.Error("NewClient() succeeded")
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	if (Config{}).Client() != http.DefaultClient {
+		t.Error("Client() of a bare Config isn't http.DefaultClient")
+	}
+	c := &http.Client{}
+	if (Config{HTTPClient: c}).Client() != c {
+		t.Error("Client() ignored HTTPClient")
+	}
+}
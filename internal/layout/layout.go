@@ -0,0 +1,135 @@
+// Package layout arranges panels on screen. A layout is a tree of splits
+// whose leaves name panels; Solve turns it into concrete rectangles for a
+// given terminal size.
+package layout
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Direction is the axis along which a split places its children.
+type Direction string
+
+const (
+	Horizontal Direction = "horizontal" // side by side
+	Vertical   Direction = "vertical"   // stacked
+)
+
+// MinWidth is the narrowest a panel may get in a horizontal split before the
+// split falls back to stacking its children, unless the split sets its own
+// min_width.
+const MinWidth = 30
+
+// Node is one region of the layout: a panel when Panel is set, otherwise a
+// split of Children. Along the parent's axis a node takes Size cells when
+// set, or a share of the remaining space proportional to Weight.
+type Node struct {
+	Panel    string    `yaml:"panel"` // panel id or type
+	Split    Direction `yaml:"split"`
+	Weight   int       `yaml:"weight"`
+	Size     int       `yaml:"size"`
+	MinWidth int       `yaml:"min_width"`
+	Children []Node    `yaml:"children"`
+}
+
+// Validate reports structural mistakes such as a split without children or
+// a node that is both a panel and a split.
+func (n Node) Validate() error {
+	if n.Weight < 0 || n.Size < 0 || n.MinWidth < 0 {
+		return errors.New("layout: weight, size and min_width must not be negative")
+	}
+	if n.Panel != "" {
+		if n.Split != "" || len(n.Children) > 0 {
+			return fmt.Errorf("layout: panel %q can't also be a split", n.Panel)
+		}
+		return nil
+	}
+	if n.Split != Horizontal && n.Split != Vertical {
+		return fmt.Errorf("layout: split must be %q or %q, got %q", Horizontal, Vertical, n.Split)
+	}
+	if len(n.Children) == 0 {
+		return errors.New("layout: split has no children")
+	}
+	for _, c := range n.Children {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Panels lists the panel names used by the layout's leaves, in order.
+func (n Node) Panels() []string {
+	if n.Panel != "" {
+		return []string{n.Panel}
+	}
+	var names []string
+	for _, c := range n.Children {
+		names = append(names, c.Panels()...)
+	}
+	return names
+}
+
+// Prune resolves each leaf's name to a panel id with resolve and drops the
+// leaves it rejects, e.g. hidden panels, along with splits left empty. A
+// split left with one child is replaced by it, keeping the split's size.
+func (n Node) Prune(resolve func(name string) (string, bool)) (Node, bool) {
+	if n.Panel != "" {
+		id, ok := resolve(n.Panel)
+		n.Panel = id
+		return n, ok
+	}
+	var children []Node
+	for _, c := range n.Children {
+		if c, ok := c.Prune(resolve); ok {
+			children = append(children, c)
+		}
+	}
+	switch len(children) {
+	case 0:
+		return Node{}, false
+	case 1:
+		only := children[0]
+		only.Weight, only.Size = n.Weight, n.Size
+		return only, true
+	}
+	n.Children = children
+	return n, true
+}
+
+// Auto is the layout used when none is configured: one panel fills the
+// screen, two sit side by side, three put one on top of a pair, and more
+// go two per row with an odd last panel spanning the full width.
+func Auto(ids []string) Node {
+	switch len(ids) {
+	case 0:
+		return Node{}
+	case 1:
+		return Node{Panel: ids[0]}
+	case 2:
+		return Node{Split: Horizontal, Children: leaves(ids)}
+	case 3:
+		return Node{Split: Vertical, Children: []Node{
+			{Panel: ids[0]},
+			{Split: Horizontal, Children: leaves(ids[1:])},
+		}}
+	}
+	root := Node{Split: Vertical}
+	for i := 0; i < len(ids); i += 2 {
+		if i+1 == len(ids) {
+			root.Children = append(root.Children, Node{Panel: ids[i]})
+			break
+		}
+		root.Children = append(root.Children, Node{Split: Horizontal, Children: leaves(ids[i : i+2])})
+	}
+	return root
+}
+
+func leaves(ids []string) []Node {
+	nodes := make([]Node, len(ids))
+	for i, id := range ids {
+		nodes[i] = Node{Panel: id}
+	}
+	return nodes
+}
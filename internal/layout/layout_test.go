@@ -0,0 +1,69 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		node Node
+		ok   bool
+	}{
+		{"panel", Node{Panel: "a"}, true},
+		{"split", Node{Split: Vertical, Children: []Node{{Panel: "a"}}}, true},
+		{"panel with children", Node{Panel: "a", Children: []Node{{Panel: "b"}}}, false},
+		{"unknown split", Node{Split: "diagonal", Children: []Node{{Panel: "a"}}}, false},
+		{"empty split", Node{Split: Horizontal}, false},
+		{"negative weight", Node{Panel: "a", Weight: -1}, false},
+		{"bad child", Node{Split: Vertical, Children: []Node{{}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.node.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	root := Node{Split: Vertical, Children: []Node{
+		{Panel: "weather"},
+		{Split: Horizontal, Size: 12, Children: []Node{{Panel: "news"}, {Panel: "crypto"}}},
+	}}
+	hidden := map[string]bool{"crypto": true}
+	got, ok := root.Prune(func(name string) (string, bool) {
+		return name + "-1", !hidden[name]
+	})
+	if !ok {
+		t.Fatal("Prune() dropped the whole layout")
+	}
+	if want := []string{"weather-1", "news-1"}; !slices.Equal(got.Panels(), want) {
+		t.Errorf("Panels() = %v, want %v", got.Panels(), want)
+	}
+	// The split left with one child is replaced by it, keeping its size.
+	if c := got.Children[1]; c.Panel != "news-1" || c.Size != 12 {
+		t.Errorf("second child = %+v, want news-1 with size 12", c)
+	}
+
+	if _, ok := root.Prune(func(string) (string, bool) { return "", false }); ok {
+		t.Error("Prune() rejecting every panel kept the layout")
+	}
+}
+
+func TestAuto(t *testing.T) {
+	for n := range 7 {
+		ids := []string{"a", "b", "c", "d", "e", "f"}[:n]
+		root := Auto(ids)
+		if !slices.Equal(root.Panels(), ids) {
+			t.Errorf("Auto(%d).Panels() = %v, want %v", n, root.Panels(), ids)
+		}
+		if n > 0 {
+			if err := root.Validate(); err != nil {
+				t.Errorf("Auto(%d) invalid: %v", n, err)
+			}
+		}
+	}
+}
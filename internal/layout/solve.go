@@ -0,0 +1,128 @@
+package layout
+
+// Rect is a region of the screen in cells.
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// Contains reports whether the cell at x, y lies inside r.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Box is a solved Node: a panel or split with its position on screen. The
+// direction of a split may differ from its Node's after a narrow-terminal
+// fallback.
+type Box struct {
+	Rect
+	Panel    string
+	Split    Direction
+	Children []Box
+}
+
+// Solve lays n out in the area r. Children of a split exactly fill it; a
+// horizontal split too narrow to give each child its minimum width stacks
+// them vertically instead.
+func Solve(n Node, r Rect) Box {
+	b := Box{Rect: r, Panel: n.Panel, Split: n.Split}
+	if n.Panel != "" {
+		return b
+	}
+
+	minWidth := n.MinWidth
+	if minWidth == 0 {
+		minWidth = MinWidth
+	}
+	fallback := n.Split == Horizontal && r.Width < minWidth*len(n.Children)
+	if fallback {
+		b.Split = Vertical
+	}
+
+	total := r.Height
+	if b.Split == Horizontal {
+		total = r.Width
+	}
+	sizes := distribute(n.Children, total, fallback)
+
+	offset := 0
+	for i, c := range n.Children {
+		cr := Rect{X: r.X, Y: r.Y + offset, Width: r.Width, Height: sizes[i]}
+		if b.Split == Horizontal {
+			cr = Rect{X: r.X + offset, Y: r.Y, Width: sizes[i], Height: r.Height}
+		}
+		b.Children = append(b.Children, Solve(c, cr))
+		offset += sizes[i]
+	}
+	return b
+}
+
+// distribute splits total cells among children: fixed sizes first, the rest
+// by weight (default 1). Rounding leftovers go to the first weighted
+// children so the sizes always add up to total. When ignoreSize is set,
+// fixed sizes (meant for the other axis) are treated as weight 1.
+func distribute(children []Node, total int, ignoreSize bool) []int {
+	sizes := make([]int, len(children))
+	remaining := total
+	weights := 0
+	for i, c := range children {
+		if c.Size > 0 && !ignoreSize {
+			sizes[i] = min(c.Size, max(remaining, 0))
+			remaining -= sizes[i]
+			continue
+		}
+		weights += weight(c)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	if weights == 0 {
+		// Only fixed sizes: give what's left to the last child.
+		sizes[len(sizes)-1] += remaining
+		return sizes
+	}
+
+	left := remaining
+	for i, c := range children {
+		if c.Size > 0 && !ignoreSize {
+			continue
+		}
+		sizes[i] = remaining * weight(c) / weights
+		left -= sizes[i]
+	}
+	for i, c := range children {
+		if left == 0 {
+			break
+		}
+		if c.Size > 0 && !ignoreSize {
+			continue
+		}
+		sizes[i]++
+		left--
+	}
+	return sizes
+}
+
+func weight(n Node) int {
+	if n.Weight > 0 {
+		return n.Weight
+	}
+	return 1
+}
+
+// At returns the panel box containing the cell at x, y, for hit-testing
+// mouse events.
+func (b Box) At(x, y int) (Box, bool) {
+	if !b.Contains(x, y) {
+		return Box{}, false
+	}
+	if b.Panel != "" {
+		return b, true
+	}
+	for _, c := range b.Children {
+		if hit, ok := c.At(x, y); ok {
+			return hit, true
+		}
+	}
+	return Box{}, false
+}
@@ -0,0 +1,113 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistribute(t *testing.T) {
+	tests := []struct {
+		name       string
+		children   []Node
+		total      int
+		ignoreSize bool
+		want       []int
+	}{
+		{"even", []Node{{}, {}}, 10, false, []int{5, 5}},
+		{"leftover to first", []Node{{}, {}, {}}, 10, false, []int{4, 3, 3}},
+		{"weights", []Node{{Weight: 1}, {Weight: 3}}, 20, false, []int{5, 15}},
+		{"fixed then weighted", []Node{{Size: 3}, {}, {}}, 10, false, []int{3, 4, 3}},
+		{"fixed larger than total", []Node{{Size: 8}, {Size: 8}, {}}, 10, false, []int{8, 2, 0}},
+		{"only fixed", []Node{{Size: 2}, {Size: 3}}, 10, false, []int{2, 8}},
+		{"sizes ignored", []Node{{Size: 3}, {}}, 10, true, []int{5, 5}},
+		{"zero total", []Node{{}, {}}, 0, false, []int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distribute(tt.children, tt.total, tt.ignoreSize)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("distribute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolve(t *testing.T) {
+	area := Rect{Width: 100, Height: 40}
+	pair := Node{Split: Horizontal, Children: []Node{{Panel: "a"}, {Panel: "b", Weight: 3}}}
+
+	tests := []struct {
+		name string
+		node Node
+		area Rect
+		want map[string]Rect
+	}{
+		{"single panel", Node{Panel: "a"}, area, map[string]Rect{
+			"a": area,
+		}},
+		{"side by side", pair, area, map[string]Rect{
+			"a": {X: 0, Y: 0, Width: 25, Height: 40},
+			"b": {X: 25, Y: 0, Width: 75, Height: 40},
+		}},
+		{"narrow falls back to stacking", pair, Rect{Width: 50, Height: 40}, map[string]Rect{
+			"a": {X: 0, Y: 0, Width: 50, Height: 10},
+			"b": {X: 0, Y: 10, Width: 50, Height: 30},
+		}},
+		{"own min width", Node{Split: Horizontal, MinWidth: 20, Children: []Node{{Panel: "a"}, {Panel: "b"}}},
+			Rect{Width: 50, Height: 40}, map[string]Rect{
+				"a": {X: 0, Y: 0, Width: 25, Height: 40},
+				"b": {X: 25, Y: 0, Width: 25, Height: 40},
+			}},
+		{"nested with offset", Node{Split: Vertical, Children: []Node{
+			{Panel: "top", Size: 10},
+			{Split: Horizontal, Children: []Node{{Panel: "a"}, {Panel: "b"}}},
+		}}, Rect{X: 1, Y: 1, Width: 80, Height: 30}, map[string]Rect{
+			"top": {X: 1, Y: 1, Width: 80, Height: 10},
+			"a":   {X: 1, Y: 11, Width: 40, Height: 20},
+			"b":   {X: 41, Y: 11, Width: 40, Height: 20},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]Rect{}
+			collect(Solve(tt.node, tt.area), got)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Solve() placed %v, want %v", got, tt.want)
+			}
+			for id, r := range tt.want {
+				if got[id] != r {
+					t.Errorf("%s = %+v, want %+v", id, got[id], r)
+				}
+			}
+		})
+	}
+}
+
+func collect(b Box, into map[string]Rect) {
+	if b.Panel != "" {
+		into[b.Panel] = b.Rect
+	}
+	for _, c := range b.Children {
+		collect(c, into)
+	}
+}
+
+func TestAt(t *testing.T) {
+	b := Solve(Node{Split: Horizontal, Children: []Node{{Panel: "a"}, {Panel: "b"}}}, Rect{Width: 80, Height: 20})
+	tests := []struct {
+		x, y int
+		want string
+	}{
+		{0, 0, "a"},
+		{39, 19, "a"},
+		{40, 0, "b"},
+		{80, 0, ""},
+		{0, 20, ""},
+	}
+	for _, tt := range tests {
+		got, _ := b.At(tt.x, tt.y)
+		if got.Panel != tt.want {
+			t.Errorf("At(%d, %d) = %q, want %q", tt.x, tt.y, got.Panel, tt.want)
+		}
+	}
+}
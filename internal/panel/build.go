@@ -0,0 +1,60 @@
+package panel
+
+import (
+	"fmt"
+
+	"pulse/internal/config"
+)
+
+// Instance is one configured panel with its unique id.
+type Instance struct {
+	ID     string
+	Def    Definition
+	Config config.PanelConfig
+	Panel  Panel
+}
+
+// Build creates the panels declared in cfg.Panels, or one of each registered
+// type that works without options when none are declared. Ids default to the
+// type name and get a numeric suffix when a type appears more than once.
+func Build(cfg config.Config) ([]Instance, error) {
+	decls := cfg.Panels
+	if len(decls) == 0 {
+		for _, d := range Definitions() {
+			if !d.ConfigOnly {
+				decls = append(decls, config.PanelConfig{Type: d.Name})
+			}
+		}
+	}
+
+	var out []Instance
+	seen := map[string]int{}
+	for _, pc := range decls {
+		d, ok := Lookup(pc.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown panel type %q", pc.Type)
+		}
+		p, err := d.New(cfg, pc)
+		if err != nil {
+			return nil, err
+		}
+
+		id := Or(pc.ID, pc.Type)
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			if pc.ID != "" {
+				return nil, fmt.Errorf("duplicate panel id %q", id)
+			}
+			id = fmt.Sprintf("%s-%d", id, n)
+		}
+
+		out = append(out, Instance{ID: id, Def: d, Config: pc, Panel: p})
+	}
+	return out, nil
+}
+
+// Shown reports whether the instance is selected by a visibility map keyed
+// by panel type or id. A nil map selects everything.
+func (in Instance) Shown(visible map[string]bool) bool {
+	return visible == nil || visible[in.Def.Name] || visible[in.ID]
+}
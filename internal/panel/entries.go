@@ -0,0 +1,80 @@
+package panel
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"pulse/internal/style"
+)
+
+// Entry is one item of a generic list panel: a title, an optional detail
+// line under it and an optional link.
+type Entry struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Entries adapts entries to Items. Each takes a line, plus one for its
+// detail; the filter matches title and detail.
+type Entries []Entry
+
+func (es Entries) Len() int { return len(es) }
+
+func (es Entries) Rows(i int) int {
+	if es[i].Detail != "" {
+		return 2
+	}
+	return 1
+}
+
+func (es Entries) Fields(i int) []string {
+	return []string{es[i].Title, es[i].Detail}
+}
+
+// View draws the entries on page with the filter's matches highlighted,
+// or a placeholder when there are none to show.
+func (es Entries) View(width int, page Page, filter Filter) []string {
+	maxText := width - 8
+	if maxText < 20 {
+		maxText = 20
+	}
+
+	var lines []string
+	for _, i := range page.Items {
+		e := es[i]
+
+		cursor := "  "
+		if i == page.Selected {
+			cursor = style.AccentStyle.Render("▸ ")
+		}
+
+		lines = append(lines, cursor+filter.Highlight(ansi.Truncate(e.Title, maxText, "..."), lipgloss.NewStyle()))
+		if e.Detail != "" {
+			lines = append(lines, fmt.Sprintf("   %s",
+				filter.Highlight(ansi.Truncate(e.Detail, maxText, "..."), style.SubtitleStyle),
+			))
+		}
+	}
+	switch {
+	case len(es) == 0:
+		lines = append(lines, style.SubtitleStyle.Render("  No items"))
+	case len(page.Items) == 0:
+		lines = append(lines, style.SubtitleStyle.Render("  No matches"))
+	}
+	return lines
+}
+
+// Summary lists the entries as plain text, detail after the title.
+func (es Entries) Summary() []Line {
+	var lines []Line
+	for _, e := range es {
+		text := e.Title
+		if e.Detail != "" {
+			text += " · " + e.Detail
+		}
+		lines = append(lines, Line{Text: text, URL: e.URL})
+	}
+	return lines
+}
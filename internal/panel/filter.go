@@ -0,0 +1,128 @@
+package panel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"pulse/internal/style"
+)
+
+// Filter is the / filter of a list panel. Items match when one of their
+// fields contains the typed text, ignoring case. The filter is kept when
+// the panel's items are replaced, so new matches show up on refresh.
+type Filter struct {
+	input   textinput.Model
+	editing bool // the query is being typed
+}
+
+// Active reports whether the filter is being typed or narrows the list.
+func (f Filter) Active() bool {
+	return f.editing || f.input.Value() != ""
+}
+
+// Captures reports whether msg belongs to the filter rather than to the
+// dashboard: every key while typing, and the match keys and Esc while a
+// filter is applied.
+func (f Filter) Captures(msg tea.KeyMsg) bool {
+	if f.editing {
+		return true
+	}
+	return f.Active() && (key.Matches(msg, Keys.NextMatch, Keys.PrevMatch) || msg.Type == tea.KeyEsc)
+}
+
+// HandleKey edits the filter and reports whether msg was for it. / starts
+// typing, Enter keeps the filter and returns to the list, Esc clears it.
+func (f *Filter) HandleKey(msg tea.KeyMsg) bool {
+	if !f.editing {
+		switch {
+		case key.Matches(msg, Keys.Filter):
+			if !f.Active() {
+				f.input = newFilterInput()
+			}
+			f.editing = true
+			f.input.Focus()
+			f.input.CursorEnd()
+			return true
+		case f.Active() && msg.Type == tea.KeyEsc:
+			f.input.Reset()
+			return true
+		}
+		return false
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		f.input.Reset()
+		fallthrough
+	case tea.KeyEnter:
+		f.editing = false
+		f.input.Blur()
+	default:
+		f.input, _ = f.input.Update(msg)
+	}
+	return true
+}
+
+func newFilterInput() textinput.Model {
+	in := textinput.New()
+	in.Prompt = "/"
+	in.Cursor.SetMode(cursor.CursorStatic)
+	return in
+}
+
+// Match reports whether any of fields contains the query.
+func (f Filter) Match(fields []string) bool {
+	q := strings.ToLower(f.input.Value())
+	if q == "" {
+		return true
+	}
+	for _, s := range fields {
+		if strings.Contains(strings.ToLower(s), q) {
+			return true
+		}
+	}
+	return false
+}
+
+// Highlight renders s in base with the parts matching the query picked
+// out. s should already be truncated to fit.
+func (f Filter) Highlight(s string, base lipgloss.Style) string {
+	q := strings.ToLower(f.input.Value())
+	lower := strings.ToLower(s)
+	if q == "" || len(lower) != len(s) {
+		return base.Render(s)
+	}
+	match := style.AccentStyle.Bold(true).Underline(true)
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			break
+		}
+		if i > 0 {
+			b.WriteString(base.Render(s[:i]))
+		}
+		b.WriteString(match.Render(s[i : i+len(q)]))
+		s, lower = s[i+len(q):], lower[i+len(q):]
+	}
+	if s != "" {
+		b.WriteString(base.Render(s))
+	}
+	return b.String()
+}
+
+// Status is shown beside the panel title while the filter is active, e.g.
+// "/rust 3 of 30".
+func (f Filter) Status(matches, total int) string {
+	if !f.Active() {
+		return ""
+	}
+	// Styled here so a theme switch reaches it.
+	f.input.PromptStyle = style.AccentStyle
+	return "  " + f.input.View() + " " + style.SubtitleStyle.Render(fmt.Sprintf("%d of %d", matches, total))
+}
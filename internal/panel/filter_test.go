@@ -0,0 +1,73 @@
+package panel
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestListFilter(t *testing.T) {
+	l := List{Rows: 6}
+	press(&l, fruit, "down", "down") // cherry
+
+	press(&l, fruit, "/", "e", "r")
+	if !l.Filter.Active() || !l.Filter.Captures(keyMsg("q")) {
+		t.Fatal("filter isn't capturing keys while typed")
+	}
+	// cherry and elderberry match; the selection stays on cherry.
+	if i, ok := l.Item(fruit); !ok || fruit.titles[i] != "cherry" {
+		t.Errorf("Item() = %d, %v; want cherry", i, ok)
+	}
+	if pg := l.Page(fruit, 6); !slices.Equal(pg.Items, []int{2, 4}) || pg.Matches != 2 {
+		t.Errorf("Page().Items = %v of %d, want [2 4] of 2", pg.Items, pg.Matches)
+	}
+
+	press(&l, fruit, "enter", "n")
+	if i, _ := l.Item(fruit); fruit.titles[i] != "elderberry" {
+		t.Errorf("n selected %q, want elderberry", fruit.titles[i])
+	}
+	press(&l, fruit, "n")
+	if i, _ := l.Item(fruit); fruit.titles[i] != "cherry" {
+		t.Errorf("n wrapped to %q, want cherry", fruit.titles[i])
+	}
+	if !l.Filter.Captures(keyMsg("N")) || !l.Filter.Captures(keyMsg("esc")) {
+		t.Error("applied filter doesn't capture N and esc")
+	}
+
+	press(&l, fruit, "/", "x", "y", "z", "enter")
+	if _, ok := l.Item(fruit); ok {
+		t.Error("Item() found a match for xyz")
+	}
+	if pg := l.Page(fruit, 6); pg.Selected != -1 || len(pg.Items) != 0 {
+		t.Errorf("Page() = %+v, want nothing selected", pg)
+	}
+
+	press(&l, fruit, "esc")
+	if l.Filter.Active() {
+		t.Error("esc didn't clear the filter")
+	}
+	if pg := l.Page(fruit, 100); pg.Matches != 7 {
+		t.Errorf("Matches = %d after clearing, want 7", pg.Matches)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	var f Filter
+	f.HandleKey(keyMsg("/"))
+	for _, r := range "Rust" {
+		f.HandleKey(keyMsg(string(r)))
+	}
+	tests := []struct {
+		fields []string
+		want   bool
+	}{
+		{[]string{"Rust 2.0 released"}, true},
+		{[]string{"Go", "trusty"}, true},
+		{[]string{"Go 1.25"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(tt.fields); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.fields, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package panel
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"pulse/internal/style"
+)
+
+// State summarises how far a panel's data can be trusted.
+type State int
+
+const (
+	Fresh      State = iota // last fetch succeeded within the expected interval
+	Refreshing              // a fetch is in flight
+	Stale                   // data is from the cache or overdue
+	Failing                 // the last fetch failed
+)
+
+func (s State) String() string {
+	switch s {
+	case Refreshing:
+		return "refreshing"
+	case Stale:
+		return "stale"
+	case Failing:
+		return "failing"
+	default:
+		return "fresh"
+	}
+}
+
+// Health tracks the freshness of a panel's data. Panels keep one, update it
+// from Fetch, Update and Restore, and render Footer at the bottom of View.
+type Health struct {
+	Interval time.Duration
+	Updated  time.Time // last successful fetch, or when the cache was saved
+	Loading  bool
+	Cached   bool // data came from the on-disk cache
+	Err      error
+	Failures int // consecutive failed fetches
+}
+
+// Start marks a fetch as in flight.
+func (h *Health) Start() { h.Loading = true }
+
+// Succeed records a successful fetch.
+func (h *Health) Succeed() {
+	h.Loading = false
+	h.Cached = false
+	h.Err = nil
+	h.Failures = 0
+	h.Updated = time.Now()
+}
+
+// Fail records a failed fetch; existing data is kept.
+func (h *Health) Fail(err error) {
+	h.Loading = false
+	h.Err = err
+	h.Failures++
+}
+
+// Restore records that data saved at t was loaded from the cache.
+func (h *Health) Restore(t time.Time) {
+	h.Updated = t
+	h.Cached = true
+}
+
+// Age is how old the displayed data is.
+func (h Health) Age() time.Duration {
+	if h.Updated.IsZero() {
+		return 0
+	}
+	return time.Since(h.Updated)
+}
+
+func (h Health) State() State {
+	switch {
+	case h.Loading:
+		return Refreshing
+	case h.Err != nil:
+		return Failing
+	case h.Cached || (h.Interval > 0 && h.Age() > 2*h.Interval):
+		return Stale
+	default:
+		return Fresh
+	}
+}
+
+// Footer renders the state, data age and last error on one line no wider
+// than width.
+func (h Health) Footer(width int) string {
+	state := h.State()
+	text := state.String()
+	if !h.Updated.IsZero() {
+		text += " · " + FormatAge(h.Age())
+		if h.Cached {
+			text += " (cached)"
+		}
+	}
+	if h.Err != nil {
+		text += fmt.Sprintf(" · %d× %s", h.Failures, shortError(h.Err))
+	}
+	text = runewidth.Truncate(text, max(width-2, 0), "…")
+
+	switch state {
+	case Fresh:
+		return style.PositiveStyle.Render("●") + " " + style.SubtitleStyle.Render(text)
+	case Refreshing:
+		return style.AccentStyle.Render("●") + " " + style.SubtitleStyle.Render(text)
+	case Stale:
+		return style.WarningStyle.Render("● " + text)
+	default:
+		return style.ErrorStyle.Render("● " + text)
+	}
+}
+
+// FormatAge renders a duration the way panels show ages, e.g. "5m ago".
+func FormatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return "just now"
+	}
+}
+
+// shortError drops the method and URL from transport errors, which would
+// otherwise fill the footer before saying what went wrong.
+func shortError(err error) string {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,70 @@
+package panel
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type KeyMap struct {
+	Up        key.Binding
+	Down      key.Binding
+	PageUp    key.Binding
+	PageDown  key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+	Filter    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+	Open      key.Binding
+}
+
+// Keys holds the bindings shared by panels that handle keys themselves. The
+// UI replaces them with the config's overrides at startup.
+var Keys = KeyMap{
+	Up:        key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+	Down:      key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
+	PageUp:    key.NewBinding(key.WithKeys("pgup"), key.WithHelp("pgup", "page up")),
+	PageDown:  key.NewBinding(key.WithKeys("pgdown"), key.WithHelp("pgdn", "page down")),
+	Top:       key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "top")),
+	Bottom:    key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "bottom")),
+	Filter:    key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+	NextMatch: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next match")),
+	PrevMatch: key.NewBinding(key.WithKeys("N"), key.WithHelp("N", "previous match")),
+	Open:      key.NewBinding(key.WithKeys("o", "enter"), key.WithHelp("o", "open")),
+}
+
+// ListBindings are the keys list panels show in help.
+func (k KeyMap) ListBindings() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.PageUp, k.PageDown, k.Top, k.Bottom,
+		k.Filter, k.NextMatch, k.PrevMatch, k.Open}
+}
+
+// Hint is the footer hint of list panels, e.g. "  ↑↓ navigate · o open · ".
+func Hint() string {
+	up, down := KeyLabel(Keys.Up.Keys()[0]), KeyLabel(Keys.Down.Keys()[0])
+	nav := up + down
+	if up != "↑" || down != "↓" {
+		nav = up + "/" + down
+	}
+	return fmt.Sprintf("  %s navigate · %s open · ", nav, KeyLabel(Keys.Open.Keys()[0]))
+}
+
+// KeyLabel is how a key name is shown in hints and help.
+func KeyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "space"
+	case "pgdown":
+		return "pgdn"
+	}
+	return k
+}
@@ -0,0 +1,233 @@
+package panel
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/style"
+)
+
+// SizeMsg tells a panel the content size its View will be called with. The
+// UI sends it whenever the layout changes.
+type SizeMsg struct {
+	Width  int
+	Height int
+}
+
+// Items is a list panel's data as List sees it.
+type Items interface {
+	Len() int
+	// Rows is how many lines item i takes on screen.
+	Rows(i int) int
+	// Fields is the text of item i the filter matches against.
+	Fields(i int) []string
+}
+
+// List is the selection, scroll offset and filter of a list panel. It
+// works on positions among the items matching the filter; methods taking
+// Items map them back to item indexes.
+type List struct {
+	Selected int // position of the selected item
+	Offset   int // position of the first item on screen
+	Rows     int // row budget from the last SizeMsg; zero fits everything
+	Filter   Filter
+}
+
+// Page is what a list panel draws: the items on screen and how many are
+// hidden above and below them.
+type Page struct {
+	Items    []int // item indexes, in order
+	Selected int   // item index of the selection, -1 when nothing matches
+	Above    int
+	Below    int
+	Matches  int // items matching the filter
+}
+
+// shown returns the indexes of the items matching the filter.
+func (l List) shown(items Items) []int {
+	var out []int
+	for i := range items.Len() {
+		if l.Filter.Match(items.Fields(i)) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// rowsOf returns the row count of each position in shown.
+func rowsOf(items Items, shown []int) func(int) int {
+	return func(p int) int { return items.Rows(shown[p]) }
+}
+
+// HandleKey applies the filter and navigation keys and reports whether msg
+// was one of them. Up and down wrap around, as do n and N, which step
+// through the matches; paging and jumping stop at the ends.
+func (l *List) HandleKey(msg tea.KeyMsg, items Items) bool {
+	before := l.shown(items)
+	if l.Filter.HandleKey(msg) {
+		// Keep the selected item if it still matches.
+		after := l.shown(items)
+		pos := 0
+		if l.Selected < len(before) {
+			pos = max(slices.Index(after, before[l.Selected]), 0)
+		}
+		l.Offset = 0
+		l.selectPos(pos, len(after), rowsOf(items, after))
+		return true
+	}
+
+	n, rows := len(before), rowsOf(items, before)
+	if n == 0 {
+		return false
+	}
+	page := max(len(l.visible(n, rows))-1, 1)
+	switch {
+	case key.Matches(msg, Keys.Down, Keys.NextMatch):
+		l.selectPos((l.Selected+1)%n, n, rows)
+	case key.Matches(msg, Keys.Up, Keys.PrevMatch):
+		l.selectPos((l.Selected-1+n)%n, n, rows)
+	case key.Matches(msg, Keys.PageDown):
+		l.selectPos(l.Selected+page, n, rows)
+	case key.Matches(msg, Keys.PageUp):
+		l.selectPos(l.Selected-page, n, rows)
+	case key.Matches(msg, Keys.Top):
+		l.selectPos(0, n, rows)
+	case key.Matches(msg, Keys.Bottom):
+		l.selectPos(n-1, n, rows)
+	default:
+		return false
+	}
+	return true
+}
+
+// Captures reports whether the list claims msg ahead of the dashboard's
+// bindings, i.e. while the filter takes keys.
+func (l List) Captures(msg tea.KeyMsg) bool {
+	return l.Filter.Captures(msg)
+}
+
+// Scroll moves the selection by delta positions, stopping at the ends.
+func (l *List) Scroll(delta int, items Items) {
+	shown := l.shown(items)
+	l.selectPos(l.Selected+delta, len(shown), rowsOf(items, shown))
+}
+
+// Clamp keeps the selection valid after the items were replaced.
+func (l *List) Clamp(items Items) {
+	l.Scroll(0, items)
+}
+
+// Resize sets the row budget and keeps the selection in view.
+func (l *List) Resize(budget int, items Items) {
+	l.Rows = max(budget, 1)
+	l.Clamp(items)
+}
+
+// SelectRow selects the item drawn at row, counted from the first item's
+// row, in a view with the given row budget.
+func (l *List) SelectRow(row int, items Items, budget int) bool {
+	shown := l.shown(items)
+	rows := rowsOf(items, shown)
+	v := *l
+	v.Rows = max(budget, 1)
+	v.follow(len(shown), rows)
+	line := 0
+	for _, p := range v.visible(len(shown), rows) {
+		if row >= line && row < line+rows(p) {
+			l.selectPos(p, len(shown), rows)
+			return true
+		}
+		line += rows(p)
+	}
+	return false
+}
+
+// Item returns the index of the selected item, if any item matches.
+func (l List) Item(items Items) (int, bool) {
+	shown := l.shown(items)
+	if len(shown) == 0 {
+		return 0, false
+	}
+	return shown[min(l.Selected, len(shown)-1)], true
+}
+
+// Page returns what to draw in budget rows, starting at the offset but
+// shifted so the selection is on screen even when the budget differs from
+// the last SizeMsg.
+func (l List) Page(items Items, budget int) Page {
+	shown := l.shown(items)
+	rows := rowsOf(items, shown)
+	l.Rows = max(budget, 1)
+	l.selectPos(l.Selected, len(shown), rows)
+
+	pg := Page{Selected: -1, Matches: len(shown)}
+	visible := l.visible(len(shown), rows)
+	for _, p := range visible {
+		pg.Items = append(pg.Items, shown[p])
+	}
+	if len(visible) > 0 {
+		pg.Selected = shown[l.Selected]
+		pg.Above = visible[0]
+		pg.Below = len(shown) - 1 - visible[len(visible)-1]
+	}
+	return pg
+}
+
+func (l *List) selectPos(p, n int, rows func(int) int) {
+	l.Selected = min(max(p, 0), max(n-1, 0))
+	l.follow(n, rows)
+}
+
+func (l *List) follow(n int, rows func(int) int) {
+	l.Offset = min(l.Offset, l.Selected)
+	for l.Offset < l.Selected && !l.fits(l.Offset, l.Selected, rows) {
+		l.Offset++
+	}
+	l.Offset = max(min(l.Offset, n-1), 0)
+}
+
+// fits reports whether positions from through to fit in the row budget.
+func (l List) fits(from, to int, rows func(int) int) bool {
+	if l.Rows <= 0 {
+		return true
+	}
+	used := 0
+	for p := from; p <= to; p++ {
+		used += rows(p)
+	}
+	return used <= l.Rows
+}
+
+// visible returns the positions that fit on screen from the offset on.
+func (l List) visible(n int, rows func(int) int) []int {
+	var out []int
+	used := 0
+	for p := l.Offset; p < n; p++ {
+		used += rows(p)
+		if l.Rows > 0 && used > l.Rows && len(out) > 0 {
+			break
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// MoreAbove is the scroll indicator drawn above the items, blank when the
+// first one is on screen.
+func (pg Page) MoreAbove() string {
+	if pg.Above == 0 {
+		return ""
+	}
+	return style.SubtitleStyle.Render(fmt.Sprintf("  ↑ %d more", pg.Above))
+}
+
+// MoreBelow is the scroll indicator drawn below the items, blank when the
+// last one is on screen.
+func (pg Page) MoreBelow() string {
+	if pg.Below == 0 {
+		return ""
+	}
+	return style.SubtitleStyle.Render(fmt.Sprintf("  ↓ %d more", pg.Below))
+}
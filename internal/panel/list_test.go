@@ -0,0 +1,132 @@
+package panel
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// items is a list of titles, each taking rows lines.
+type items struct {
+	titles []string
+	rows   int
+}
+
+func (it items) Len() int              { return len(it.titles) }
+func (it items) Rows(int) int          { return it.rows }
+func (it items) Fields(i int) []string { return []string{it.titles[i]} }
+
+var fruit = items{titles: []string{"apple", "banana", "cherry", "date", "elderberry", "fig", "grape"}, rows: 2}
+
+func keyMsg(s string) tea.KeyMsg {
+	switch s {
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "pgdown":
+		return tea.KeyMsg{Type: tea.KeyPgDown}
+	case "pgup":
+		return tea.KeyMsg{Type: tea.KeyPgUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "home":
+		return tea.KeyMsg{Type: tea.KeyHome}
+	case "end":
+		return tea.KeyMsg{Type: tea.KeyEnd}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func press(l *List, it Items, keys ...string) {
+	for _, k := range keys {
+		l.HandleKey(keyMsg(k), it)
+	}
+}
+
+func TestListNavigation(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		selected int
+		offset   int
+	}{
+		{"down", []string{"down", "j"}, 2, 0},
+		{"up wraps", []string{"up"}, 6, 4},
+		{"down wraps", []string{"end", "down"}, 0, 0},
+		{"bottom", []string{"end"}, 6, 4},
+		{"top", []string{"end", "home"}, 0, 0},
+		{"page down", []string{"pgdown"}, 2, 0},
+		{"page down stops at the end", []string{"pgdown", "pgdown", "pgdown", "pgdown"}, 6, 4},
+		{"page up stops at the start", []string{"down", "pgup"}, 0, 0},
+		{"scrolls to follow", []string{"down", "down", "down"}, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Three two-row items fit on screen.
+			l := List{Rows: 6}
+			press(&l, fruit, tt.keys...)
+			if l.Selected != tt.selected || l.Offset != tt.offset {
+				t.Errorf("Selected, Offset = %d, %d; want %d, %d", l.Selected, l.Offset, tt.selected, tt.offset)
+			}
+		})
+	}
+}
+
+func TestListPage(t *testing.T) {
+	l := List{Rows: 6}
+	press(&l, fruit, "down", "down", "down")
+
+	pg := l.Page(fruit, 6)
+	want := Page{Items: []int{1, 2, 3}, Selected: 3, Above: 1, Below: 3, Matches: 7}
+	if !slices.Equal(pg.Items, want.Items) || pg.Selected != want.Selected ||
+		pg.Above != want.Above || pg.Below != want.Below || pg.Matches != want.Matches {
+		t.Errorf("Page() = %+v, want %+v", pg, want)
+	}
+
+	// A smaller budget than the last SizeMsg still shows the selection.
+	pg = l.Page(fruit, 2)
+	if !slices.Equal(pg.Items, []int{3}) {
+		t.Errorf("Page(2).Items = %v, want [3]", pg.Items)
+	}
+
+	// An item taller than the budget is still drawn.
+	pg = List{}.Page(fruit, 0)
+	if !slices.Equal(pg.Items, []int{0}) {
+		t.Errorf("Page(0).Items = %v, want [0]", pg.Items)
+	}
+}
+
+func TestSelectRow(t *testing.T) {
+	tests := []struct {
+		row  int
+		ok   bool
+		want int
+	}{
+		{0, true, 0},
+		{1, true, 0},
+		{2, true, 1},
+		{5, true, 2},
+		{6, false, 0},
+	}
+	for _, tt := range tests {
+		var l List
+		ok := l.SelectRow(tt.row, fruit, 6)
+		if ok != tt.ok || l.Selected != tt.want {
+			t.Errorf("SelectRow(%d) = %v, selected %d; want %v, %d", tt.row, ok, l.Selected, tt.ok, tt.want)
+		}
+	}
+}
+
+func TestClamp(t *testing.T) {
+	l := List{Rows: 6}
+	press(&l, fruit, "end")
+	short := items{titles: fruit.titles[:2], rows: 2}
+	l.Clamp(short)
+	if l.Selected != 1 || l.Offset != 1 {
+		t.Errorf("Selected, Offset = %d, %d; want 1, 1", l.Selected, l.Offset)
+	}
+}
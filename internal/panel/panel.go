@@ -0,0 +1,157 @@
+// Package panel defines the interface every dashboard panel implements and the
+// registry the root model builds its panels from.
+package panel
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/config"
+)
+
+// Panel is a self-contained dashboard widget. Implementations follow the
+// Elm Architecture like tea.Model, but render into a box sized by the root
+// layout and return themselves as Panel so the root can store them uniformly.
+//
+// Panels don't poll on their own: the root scheduler calls Fetch every
+// Interval and hands the resulting message back through Update.
+type Panel interface {
+	Init() tea.Cmd
+	Update(msg tea.Msg) (Panel, tea.Cmd)
+	Fetch() (Panel, tea.Cmd)
+	Interval() time.Duration
+	Health() Health
+	View(width, height int) string
+	Title() string
+	HandleKey(msg tea.KeyMsg) (Panel, tea.Cmd)
+	SelectedURL() string
+}
+
+// Factory builds one panel instance from the global configuration and the
+// instance's own entry in the config file.
+type Factory func(cfg config.Config, pc config.PanelConfig) (Panel, error)
+
+// Definition describes a panel type that can be placed on the dashboard.
+type Definition struct {
+	Name   string // unique id, also used as the CLI flag name
+	Toggle string // key that shows/hides the panel
+	New    Factory
+
+	// ConfigOnly types need options from the config file and are left out
+	// of the default dashboard.
+	ConfigOnly bool
+}
+
+// Result is implemented by the messages fetch commands produce, so the
+// scheduler can tell failures apart and back off.
+type Result interface {
+	FetchError() error
+}
+
+// Throttled is implemented by results that carry a time before which the
+// next fetch must not start, such as an exhausted API quota.
+type Throttled interface {
+	NotBefore() time.Time
+}
+
+// Background is implemented by command messages a panel must receive even
+// while hidden, such as the result of follow-up work it tracks as in
+// flight. Other messages from a hidden panel's commands are dropped.
+type Background interface {
+	Background()
+}
+
+// Cacheable is implemented by panels whose last successful payload is
+// persisted between runs. Snapshot returns the data to save; Restore loads
+// it back on startup, marking it stale until a fresh fetch succeeds.
+type Cacheable interface {
+	Snapshot() any
+	Restore(data []byte, saved time.Time) (Panel, error)
+}
+
+// Line is one item of a panel's plain-text summary. URL is optional.
+type Line struct {
+	Text string
+	URL  string
+}
+
+// Summarizer is implemented by panels that can describe their data without
+// styling, for headless output such as `pulse --once`.
+type Summarizer interface {
+	Summary() []Line
+}
+
+// KeyHelper is implemented by panels that handle keys themselves, so the
+// help overlay can list what the focused panel supports.
+type KeyHelper interface {
+	KeyBindings() []key.Binding
+}
+
+// Selectable is implemented by list panels so the mouse can select items.
+// Rows count from the top of the content View drew for the given height.
+type Selectable interface {
+	// SelectAt selects the item drawn at row and reports whether there
+	// was one.
+	SelectAt(row, height int) (Panel, bool)
+	// Scroll moves the selection by delta items, stopping at the ends.
+	Scroll(delta int) Panel
+}
+
+// Filterer is implemented by list panels with a / filter. Keys it captures
+// go to the panel's HandleKey before the dashboard's own bindings, so a
+// filter can be typed and n can step through matches instead of toggling.
+type Filterer interface {
+	Captures(msg tea.KeyMsg) bool
+}
+
+// Action is an entry a panel adds to the command palette.
+type Action struct {
+	Name    string // e.g. "Add coin"
+	Prompt  string // set when the action takes an argument, e.g. "Coin id"
+	Refresh bool   // fetch right after running, e.g. because the query changed
+}
+
+// Actor is implemented by panels that contribute command palette actions.
+// RunAction is called on the current panel with the chosen action's name
+// and the argument typed for it.
+type Actor interface {
+	Actions() []Action
+	RunAction(name, arg string) (Panel, error)
+}
+
+var registry []Definition
+
+// Register adds a panel type to the registry. Panels appear on the dashboard
+// in registration order. Registering the same name twice panics.
+func Register(d Definition) {
+	if _, ok := Lookup(d.Name); ok {
+		panic("panel: duplicate registration of " + d.Name)
+	}
+	registry = append(registry, d)
+}
+
+// Lookup returns the definition registered under name.
+func Lookup(name string) (Definition, bool) {
+	for _, d := range registry {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Definitions returns all registered panel types in registration order.
+func Definitions() []Definition {
+	return append([]Definition(nil), registry...)
+}
+
+// Or returns v, or fallback when v is the zero value. Panels use it to apply
+// defaults to optional config fields.
+func Or[T comparable](v, fallback T) T {
+	var zero T
+	if v == zero {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,20 @@
+package panel
+
+import (
+	"github.com/charmbracelet/bubbles/spinner"
+	"pulse/internal/style"
+)
+
+// NewSpinner returns the spinner panels show until their first data.
+func NewSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	return s
+}
+
+// SpinnerView renders s in the current theme's accent colour. The style is
+// read here rather than stored in s so a theme switch reaches it.
+func SpinnerView(s spinner.Model) string {
+	s.Style = style.AccentStyle
+	return s.View()
+}
@@ -0,0 +1,24 @@
+// Package panels registers the panel types that ship with pulse.
+package panels
+
+import (
+	"pulse/internal/panel"
+	"pulse/internal/panels/command"
+	"pulse/internal/panels/crypto"
+	"pulse/internal/panels/feed"
+	"pulse/internal/panels/github"
+	"pulse/internal/panels/httpjson"
+	"pulse/internal/panels/news"
+	"pulse/internal/panels/weather"
+)
+
+// Registration order is the default dashboard order.
+func init() {
+	panel.Register(weather.Definition)
+	panel.Register(crypto.Definition)
+	panel.Register(news.Definition)
+	panel.Register(github.Definition)
+	panel.Register(httpjson.Definition)
+	panel.Register(command.Definition)
+	panel.Register(feed.Definition)
+}
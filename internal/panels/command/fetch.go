@@ -0,0 +1,132 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/panel"
+)
+
+// waitDelay is how long a run waits for its output once the command has
+// exited or been killed.
+const waitDelay = time.Second
+
+func FetchCmd(opts options) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+		defer cancel()
+
+		cmd := shell(ctx, opts.Command)
+		isolate(cmd)
+		// Output pipes held open by background children mustn't block the
+		// run past the deadline either.
+		cmd.WaitDelay = waitDelay
+		cmd.Dir = opts.Dir
+		cmd.Env = os.Environ()
+		for k, v := range opts.Env {
+			cmd.Env = append(cmd.Env, k+"="+os.ExpandEnv(v))
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		err := cmd.Run()
+		if ctx.Err() == context.DeadlineExceeded {
+			return ResponseMsg{Error: fmt.Errorf("timed out after %s", opts.Timeout)}
+		}
+		// The command itself finished; a daemon it left behind is not a
+		// failure.
+		if errors.Is(err, exec.ErrWaitDelay) {
+			err = nil
+		}
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			if msg := firstLine(stderr.String()); msg != "" {
+				return ResponseMsg{Error: fmt.Errorf("exit %d: %s", exit.ExitCode(), msg)}
+			}
+			return ResponseMsg{Error: fmt.Errorf("exit %d", exit.ExitCode())}
+		}
+		if err != nil {
+			return ResponseMsg{Error: fmt.Errorf("run failed: %w", err)}
+		}
+
+		if opts.Format == "json" {
+			var items panel.Entries
+			if err := json.Unmarshal(stdout.Bytes(), &items); err != nil {
+				return ResponseMsg{Error: fmt.Errorf("decode failed: %w", err)}
+			}
+			for i, item := range items {
+				items[i].Title = sanitize(flatten(item.Title))
+				items[i].Detail = sanitize(flatten(item.Detail))
+			}
+			return ResponseMsg{Output: Output{Items: items}}
+		}
+
+		text := strings.TrimRight(stdout.String(), "\n")
+		var lines []string
+		if text != "" {
+			for _, line := range strings.Split(text, "\n") {
+				lines = append(lines, sanitize(line))
+			}
+		}
+		return ResponseMsg{Output: Output{Lines: lines}}
+	}
+}
+
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// flatten joins a multi-line JSON field into the single line it is drawn
+// on.
+func flatten(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+var (
+	sgrPattern    = regexp.MustCompile(`^\x1b\[[0-9;:]*m$`)
+	escapePattern = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+)
+
+// sanitize keeps colour and text attributes (SGR sequences) but drops every
+// other escape, such as cursor movement or screen clearing, that would break
+// out of the panel. Carriage returns keep only the text after the last one,
+// the way a terminal shows progress output, and tabs become spaces.
+func sanitize(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	line = strings.ReplaceAll(line, "\t", "    ")
+	line = escapePattern.ReplaceAllStringFunc(line, func(seq string) string {
+		if sgrPattern.MatchString(seq) {
+			return seq
+		}
+		return ""
+	})
+	if strings.Contains(line, "\x1b[") {
+		line += "\x1b[0m"
+	}
+	return line
+}
@@ -0,0 +1,100 @@
+package command
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"pulse/internal/panel"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"plain", "hello", "hello"},
+		{"colour kept", "\x1b[1;32mok\x1b[0m", "\x1b[1;32mok\x1b[0m\x1b[0m"},
+		{"cursor movement", "\x1b[2J\x1b[Htop\x1b[3A", "top"},
+		{"title", "\x1b]0;window title\x07text", "text"},
+		{"hyperlink", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"progress", "10%\r50%\r100%", "100%"},
+		{"crlf", "done\r", "done"},
+		{"tab", "a\tb", "a    b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.line); got != tt.want {
+				t.Errorf("sanitize(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// run runs command through FetchCmd with sh, which the tests rely on.
+func run(t *testing.T, opts options) ResponseMsg {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return FetchCmd(opts)().(ResponseMsg)
+}
+
+func TestFetchCmdText(t *testing.T) {
+	got := run(t, options{Command: `printf 'one\n\033[2Jtwo\n0%%\r100%%\n'`, Format: "text"})
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := []string{"one", "two", "100%"}
+	if !slices.Equal(got.Output.Lines, want) {
+		t.Errorf("lines = %q, want %q", got.Output.Lines, want)
+	}
+}
+
+func TestFetchCmdJSON(t *testing.T) {
+	got := run(t, options{
+		Command: `printf '%s' '[{"title": "Build\n  #12", "detail": "\u001b[31mfailed\u001b[0m", "url": "https://ci.example.com/12"}, {"title": "Deploy"}]'`,
+		Format:  "json",
+	})
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := panel.Entries{
+		{Title: "Build #12", Detail: "\x1b[31mfailed\x1b[0m\x1b[0m", URL: "https://ci.example.com/12"},
+		{Title: "Deploy"},
+	}
+	if !slices.Equal(got.Output.Items, want) {
+		t.Errorf("items = %q, want %q", got.Output.Items, want)
+	}
+
+	got = run(t, options{Command: "echo not json", Format: "json"})
+	if got.Error == nil || !strings.HasPrefix(got.Error.Error(), "decode failed") {
+		t.Errorf("error = %v, want a decode error", got.Error)
+	}
+}
+
+func TestFetchCmdExit(t *testing.T) {
+	got := run(t, options{Command: "echo out; echo 'bad flag\nusage' >&2; exit 3", Format: "text"})
+	if got.Error == nil || got.Error.Error() != "exit 3: bad flag" {
+		t.Errorf("error = %v, want exit 3: bad flag", got.Error)
+	}
+}
+
+func TestFetchCmdTimeout(t *testing.T) {
+	// The background sleep keeps stdout open: unless the whole process
+	// group is killed, the run hangs on it until waitDelay.
+	start := time.Now()
+	got := run(t, options{Command: "sleep 10 & sleep 10", Format: "text", Timeout: 100 * time.Millisecond})
+	if got.Error == nil || got.Error.Error() != "timed out after 100ms" {
+		t.Errorf("error = %v, want a timeout", got.Error)
+	}
+	if runtime.GOOS != "windows" && time.Since(start) >= waitDelay {
+		t.Errorf("run took %s; the background process outlived the timeout", time.Since(start))
+	}
+}
@@ -0,0 +1,206 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/style"
+)
+
+// Model runs a shell command on every refresh and shows what it printed,
+// either verbatim (like watch) or as a list of items in json mode.
+type Model struct {
+	opts    options
+	title   string
+	output  Output
+	list    panel.List
+	health  panel.Health
+	spinner spinner.Model
+}
+
+// Definition registers the command panel type.
+var Definition = panel.Definition{
+	Name:   "command",
+	Toggle: "x",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+	ConfigOnly: true,
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{Format: "text", Timeout: 30 * time.Second}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	if opts.Command == "" {
+		return Model{}, errors.New("command panel: command is required")
+	}
+	if opts.Format != "text" && opts.Format != "json" {
+		return Model{}, fmt.Errorf("command panel: unknown format %q (want text or json)", opts.Format)
+	}
+
+	s := panel.NewSpinner()
+	return Model{
+		opts:  opts,
+		title: panel.Or(pc.Title, opts.Command),
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, time.Minute),
+			Loading:  true,
+		},
+		spinner: s,
+	}, nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.opts)
+}
+
+func (m Model) Snapshot() any { return m.output }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.output); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResponseMsg:
+		if msg.Error != nil {
+			m.health.Fail(msg.Error)
+		} else {
+			m.output = msg.Output
+			m.health.Succeed()
+			m.list.Clamp(m.output.Items)
+		}
+		return m, nil
+
+	case panel.SizeMsg:
+		m.list.Resize(listRows(msg.Height), m.output.Items)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	if m.opts.Format != "json" {
+		return m, nil
+	}
+	m.list.HandleKey(msg, m.output.Items)
+	return m, nil
+}
+
+func (m Model) Captures(msg tea.KeyMsg) bool {
+	return m.opts.Format == "json" && m.list.Captures(msg)
+}
+
+func (m Model) KeyBindings() []key.Binding {
+	if m.opts.Format != "json" {
+		return nil
+	}
+	return panel.Keys.ListBindings()
+}
+
+// listRows is the room for items at the given height, leaving the title,
+// the scroll indicators and the footer.
+func listRows(height int) int { return height - 4 }
+
+func (m Model) SelectAt(row, height int) (panel.Panel, bool) {
+	ok := m.list.SelectRow(row-2, m.output.Items, listRows(height))
+	return m, ok
+}
+
+func (m Model) Scroll(delta int) panel.Panel {
+	m.list.Scroll(delta, m.output.Items)
+	return m
+}
+
+func (m Model) SelectedURL() string {
+	i, ok := m.list.Item(m.output.Items)
+	if !ok {
+		return ""
+	}
+	return m.output.Items[i].URL
+}
+
+func (m Model) Summary() []panel.Line {
+	var lines []panel.Line
+	for _, line := range m.output.Lines {
+		lines = append(lines, panel.Line{Text: ansi.Strip(line)})
+	}
+	return append(lines, m.output.Items.Summary()...)
+}
+
+func (m Model) View(width, height int) string {
+	title := style.TitleStyle.Render("💻 " + m.title)
+
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Running...", title, panel.SpinnerView(m.spinner))
+	}
+
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
+	}
+
+	if m.opts.Format == "json" {
+		page := m.list.Page(m.output.Items, listRows(height))
+		lines := []string{title + m.list.Filter.Status(page.Matches, len(m.output.Items)), page.MoreAbove()}
+		lines = append(lines, m.output.Items.View(width, page, m.list.Filter)...)
+		lines = append(lines, page.MoreBelow())
+		hint := style.SubtitleStyle.Render(panel.Hint())
+		lines = append(lines, hint+m.health.Footer(width-lipgloss.Width(hint)))
+		return strings.Join(lines, "\n")
+	}
+
+	var lines []string
+	lines = append(lines, title)
+	lines = append(lines, "")
+
+	maxLines := height - 4
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	for i, line := range m.output.Lines {
+		if i == maxLines {
+			break
+		}
+		lines = append(lines, "  "+ansi.Truncate(line, width-2, "…"))
+	}
+	if len(m.output.Lines) == 0 {
+		lines = append(lines, style.SubtitleStyle.Render("  No output"))
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, "  "+m.health.Footer(width-2))
+	return strings.Join(lines, "\n")
+}
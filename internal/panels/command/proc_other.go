@@ -0,0 +1,9 @@
+//go:build !unix
+
+package command
+
+import "os/exec"
+
+// isolate is a no-op where there are no process groups; WaitDelay still
+// bounds how long a run can hang on its output.
+func isolate(cmd *exec.Cmd) {}
@@ -0,0 +1,29 @@
+package command
+
+import (
+	"time"
+
+	"pulse/internal/panel"
+)
+
+// Output is what a run produced: raw lines in text mode, the entries of the
+// script's JSON output in json mode.
+type Output struct {
+	Lines []string      `json:"lines,omitempty"`
+	Items panel.Entries `json:"items,omitempty"`
+}
+
+type options struct {
+	Command string            `yaml:"command"` // run with sh -c
+	Dir     string            `yaml:"dir"`
+	Env     map[string]string `yaml:"env"`
+	Format  string            `yaml:"format"` // text (default) or json
+	Timeout time.Duration     `yaml:"timeout"`
+}
+
+type ResponseMsg struct {
+	Output Output
+	Error  error
+}
+
+func (m ResponseMsg) FetchError() error { return m.Error }
@@ -0,0 +1,236 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+	"pulse/internal/terminal"
+)
+
+// Alerts configures the crypto panel's price alerts.
+type Alerts struct {
+	Rules []Rule `yaml:"rules"`
+	// Notify is how a firing rule gets attention beyond the panel: bell,
+	// osc9, osc777 or none.
+	Notify string `yaml:"notify"`
+	// Hysteresis is how far a value must fall back past a threshold before
+	// its rule can fire again: percent of the level for price rules,
+	// percentage points for move and change rules.
+	Hysteresis float64 `yaml:"hysteresis"`
+	// Log is a file every alert is appended to, if set.
+	Log string `yaml:"log"`
+}
+
+// Rule is one alert condition. Exactly one of Above, Below, Move and
+// Change24h is set. Price levels are in the panel's first currency.
+type Rule struct {
+	Coin      string        `yaml:"coin"` // id or symbol; empty for every coin
+	Above     float64       `yaml:"above"`
+	Below     float64       `yaml:"below"`
+	Move      float64       `yaml:"move"` // percent either way within Within
+	Within    time.Duration `yaml:"within"`
+	Change24h float64       `yaml:"change_24h"` // percent either way
+}
+
+// Alert is an entry in the alert log.
+type Alert struct {
+	At   time.Time
+	Coin string
+	Text string
+}
+
+const (
+	flashFor   = 10 * time.Second
+	maxAlerts  = 50
+	moveWindow = time.Hour
+)
+
+func (a *Alerts) validate() error {
+	switch a.Notify {
+	case "":
+		a.Notify = "bell"
+	case "bell", "osc9", "osc777", "none":
+	default:
+		return fmt.Errorf("crypto panel: alerts: notify must be bell, osc9, osc777 or none, not %q", a.Notify)
+	}
+	if a.Hysteresis < 0 {
+		return errors.New("crypto panel: alerts: hysteresis can't be negative")
+	}
+	for i := range a.Rules {
+		r := &a.Rules[i]
+		set := 0
+		for _, v := range []float64{r.Above, r.Below, r.Move, r.Change24h} {
+			if v < 0 {
+				return fmt.Errorf("crypto panel: alerts: rule %d: values must be positive", i+1)
+			}
+			if v > 0 {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("crypto panel: alerts: rule %d needs exactly one of above, below, move, change_24h", i+1)
+		}
+		if r.Move > 0 && r.Within == 0 {
+			r.Within = moveWindow
+		}
+	}
+	return nil
+}
+
+// matches reports whether the rule watches coin.
+func (r Rule) matches(coin CoinData) bool {
+	return r.Coin == "" || strings.EqualFold(r.Coin, coin.ID) || strings.EqualFold(r.Coin, coin.Symbol)
+}
+
+// sample is a price seen at a time, kept for move rules.
+type sample struct {
+	at    time.Time
+	price float64
+}
+
+// alertState is what the rules remember between fetches. It lives behind
+// a pointer so copies of the panel share it.
+type alertState struct {
+	fired   map[string]bool // rule/coin pairs waiting to re-arm
+	history map[string][]sample
+	flash   map[string]time.Time // coin id → flashing until
+	log     []Alert
+}
+
+func newAlertState() *alertState {
+	return &alertState{
+		fired:   map[string]bool{},
+		history: map[string][]sample{},
+		flash:   map[string]time.Time{},
+	}
+}
+
+// check evaluates the rules against fresh prices and returns the alerts
+// that fired. A rule fires once when its condition starts to hold and is
+// re-armed when the value has moved back past the threshold by the
+// hysteresis.
+func (s *alertState) check(cfg Alerts, coins []CoinData, now time.Time) []Alert {
+	var window time.Duration
+	for _, r := range cfg.Rules {
+		window = max(window, r.Within)
+	}
+	for _, c := range coins {
+		h := append(s.history[c.ID], sample{now, c.Price})
+		for len(h) > 1 && now.Sub(h[0].at) > window {
+			h = h[1:]
+		}
+		s.history[c.ID] = h
+	}
+
+	var out []Alert
+	for i, r := range cfg.Rules {
+		for _, c := range coins {
+			if !r.matches(c) {
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", i, c.ID)
+			trigger, rearm, text := r.eval(c, s.history[c.ID], now, cfg.Hysteresis)
+			switch {
+			case !s.fired[key] && trigger:
+				s.fired[key] = true
+				a := Alert{At: now, Coin: c.Symbol, Text: text}
+				out = append(out, a)
+				s.log = append(s.log, a)
+				s.flash[c.ID] = now.Add(flashFor)
+			case s.fired[key] && rearm:
+				delete(s.fired, key)
+			}
+		}
+	}
+	if len(s.log) > maxAlerts {
+		s.log = s.log[len(s.log)-maxAlerts:]
+	}
+	return out
+}
+
+// eval reports whether the rule's condition holds for coin, whether it is
+// clear of the threshold by the hysteresis h, and the alert text. Moves are
+// measured from the oldest sample within the rule's own window; history
+// covers the longest window of all rules.
+func (r Rule) eval(c CoinData, history []sample, now time.Time, h float64) (trigger, rearm bool, text string) {
+	switch {
+	case r.Below > 0:
+		return c.Price < r.Below, c.Price > r.Below*(1+h/100),
+			fmt.Sprintf("%s below %s at %s", c.Symbol, formatPrice(r.Below, c.Currency), formatPrice(c.Price, c.Currency))
+	case r.Above > 0:
+		return c.Price > r.Above, c.Price < r.Above*(1-h/100),
+			fmt.Sprintf("%s above %s at %s", c.Symbol, formatPrice(r.Above, c.Currency), formatPrice(c.Price, c.Currency))
+	case r.Change24h > 0:
+		v := math.Abs(c.Change24h)
+		return v > r.Change24h, v < r.Change24h-h,
+			fmt.Sprintf("%s 24h change %+.1f%%", c.Symbol, c.Change24h)
+	default:
+		i := slices.IndexFunc(history, func(s sample) bool { return now.Sub(s.at) <= r.Within })
+		base := history[i]
+		if base.price == 0 {
+			return false, true, ""
+		}
+		move := (c.Price/base.price - 1) * 100
+		return math.Abs(move) >= r.Move, math.Abs(move) < r.Move-h,
+			fmt.Sprintf("%s moved %+.1f%% within %s to %s", c.Symbol, move,
+				shortDuration(r.Within), formatPrice(c.Price, c.Currency))
+	}
+}
+
+// shortDuration formats whole hours and minutes as "1h" or "15m" rather
+// than "1h0m0s".
+func shortDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
+
+// flashing reports whether coin's row should be highlighted right now. Rows
+// blink for a while after one of the coin's rules fired.
+func (s *alertState) flashing(id string, now time.Time) bool {
+	return now.Before(s.flash[id]) && now.UnixMilli()/500%2 == 0
+}
+
+// alertCmd notifies the terminal and appends to the log file. Notifications
+// go through the program's output so they don't split a frame.
+func alertCmd(cfg Alerts, alerts []Alert) tea.Cmd {
+	if len(alerts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		out := termenv.NewOutput(terminal.Stdout)
+		for _, a := range alerts {
+			switch cfg.Notify {
+			case "bell":
+				out.WriteString("\a")
+			case "osc9":
+				out.WriteString(termenv.OSC + "9;" + a.Text + termenv.ST)
+			case "osc777":
+				out.Notify("pulse", a.Text)
+			}
+		}
+		// Failing to log is not worth interrupting the dashboard for.
+		if cfg.Log != "" {
+			f, err := os.OpenFile(os.ExpandEnv(cfg.Log), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+			if err != nil {
+				return nil
+			}
+			defer f.Close()
+			for _, a := range alerts {
+				fmt.Fprintf(f, "%s %s\n", a.At.Format(time.RFC3339), a.Text)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+package crypto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertHysteresis(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		prices []float64 // one fetch each
+		change []float64 // 24h change per fetch, when the rule uses it
+		fires  []bool
+	}{
+		{
+			name:   "above",
+			rule:   Rule{Above: 100},
+			prices: []float64{90, 101, 105, 99, 101, 97, 102},
+			// 99 is within 2% of the level, so the rule stays fired until 97.
+			fires: []bool{false, true, false, false, false, false, true},
+		},
+		{
+			name:   "below",
+			rule:   Rule{Below: 100},
+			prices: []float64{110, 99, 95, 101, 99, 103, 98},
+			fires:  []bool{false, true, false, false, false, false, true},
+		},
+		{
+			name:   "24h change either way",
+			rule:   Rule{Change24h: 10},
+			prices: []float64{1, 1, 1, 1, 1, 1},
+			change: []float64{5, 11, 9, 7, -12, -12},
+			fires:  []bool{false, true, false, false, true, false},
+		},
+		{
+			name:   "below the threshold from the start",
+			rule:   Rule{Below: 100},
+			prices: []float64{50, 50, 150, 50},
+			fires:  []bool{true, false, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Alerts{Rules: []Rule{tt.rule}, Hysteresis: 2}
+			s := newAlertState()
+			now := time.Now()
+			for i, p := range tt.prices {
+				c := CoinData{ID: "bitcoin", Symbol: "BTC", Currency: "usd", Price: p}
+				if tt.change != nil {
+					c.Change24h = tt.change[i]
+				}
+				got := s.check(cfg, []CoinData{c}, now.Add(time.Duration(i)*time.Minute))
+				if fired := len(got) > 0; fired != tt.fires[i] {
+					t.Errorf("fetch %d at %v: fired %v, want %v", i, p, fired, tt.fires[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAlertMove(t *testing.T) {
+	cfg := Alerts{Rules: []Rule{{Coin: "btc", Move: 5, Within: 10 * time.Minute}}, Hysteresis: 1}
+	s := newAlertState()
+	start := time.Now()
+
+	steps := []struct {
+		after time.Duration
+		price float64
+		fires bool
+	}{
+		{0, 100, false},
+		{5 * time.Minute, 104, false},
+		{8 * time.Minute, 106, true},
+		// The window has moved past the 100 sample, so the move from 104
+		// is under 4% and the rule re-arms.
+		{14 * time.Minute, 107, false},
+		{17 * time.Minute, 106, false},
+		// Down 6.5% from 107.
+		{20 * time.Minute, 100, true},
+	}
+	for i, st := range steps {
+		c := CoinData{ID: "bitcoin", Symbol: "BTC", Currency: "usd", Price: st.price}
+		got := s.check(cfg, []CoinData{c}, start.Add(st.after))
+		if fired := len(got) > 0; fired != st.fires {
+			t.Errorf("step %d at %v: fired %v (%v), want %v", i, st.price, fired, got, st.fires)
+		}
+	}
+	if len(s.log) != 2 {
+		t.Errorf("log has %d alerts, want 2", len(s.log))
+	}
+}
+
+func TestAlertMoveWindows(t *testing.T) {
+	// The longer rule keeps an hour of history; the shorter one must still
+	// only look back 15 minutes.
+	cfg := Alerts{Rules: []Rule{
+		{Move: 5, Within: 15 * time.Minute},
+		{Move: 50, Within: time.Hour},
+	}}
+	s := newAlertState()
+	start := time.Now()
+
+	steps := []struct {
+		after time.Duration
+		price float64
+		fires bool
+	}{
+		{0, 100, false},
+		{20 * time.Minute, 103, false},
+		{40 * time.Minute, 104, false},
+		// Up 6% in the hour but only 1.9% since 40 minutes.
+		{50 * time.Minute, 106, false},
+		// Up 6.6% since 50 minutes.
+		{60 * time.Minute, 113, true},
+	}
+	for i, st := range steps {
+		c := CoinData{ID: "bitcoin", Symbol: "BTC", Currency: "usd", Price: st.price}
+		got := s.check(cfg, []CoinData{c}, start.Add(st.after))
+		if fired := len(got) > 0; fired != st.fires {
+			t.Errorf("step %d at %v: fired %v (%v), want %v", i, st.price, fired, got, st.fires)
+		}
+	}
+	if len(s.log) != 1 || s.log[0].Text != "BTC moved +6.6% within 15m to $113" {
+		t.Errorf("log = %+v, want one 15m move", s.log)
+	}
+}
+
+func TestAlertRuleCoin(t *testing.T) {
+	cfg := Alerts{Rules: []Rule{{Coin: "ETH", Above: 1}}}
+	s := newAlertState()
+	now := time.UnixMilli(1_000_000) // on a flash
+	got := s.check(cfg, []CoinData{
+		{ID: "bitcoin", Symbol: "BTC", Currency: "usd", Price: 60000},
+		{ID: "ethereum", Symbol: "ETH", Currency: "usd", Price: 3000},
+	}, now)
+	if len(got) != 1 || got[0].Coin != "ETH" || got[0].Text != "ETH above $1 at $3,000" {
+		t.Errorf("check() = %+v, want one ETH alert", got)
+	}
+	if !s.flashing("ethereum", now) {
+		t.Error("ethereum isn't flashing after its alert")
+	}
+	if s.flashing("ethereum", now.Add(flashFor)) {
+		t.Error("ethereum is still flashing after the flash period")
+	}
+	if s.flashing("bitcoin", now) {
+		t.Error("bitcoin is flashing without an alert")
+	}
+}
+
+func TestAlertsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		alerts Alerts
+		ok     bool
+	}{
+		{"defaults", Alerts{Rules: []Rule{{Above: 1}}}, true},
+		{"unknown notify", Alerts{Notify: "email"}, false},
+		{"negative hysteresis", Alerts{Hysteresis: -1}, false},
+		{"no condition", Alerts{Rules: []Rule{{Coin: "btc"}}}, false},
+		{"two conditions", Alerts{Rules: []Rule{{Above: 2, Below: 1}}}, false},
+		{"negative value", Alerts{Rules: []Rule{{Move: -5}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.alerts.validate(); (err == nil) != tt.ok {
+				t.Errorf("validate() = %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+
+	a := Alerts{Rules: []Rule{{Move: 5}}}
+	a.validate()
+	if a.Notify != "bell" || a.Rules[0].Within != moveWindow {
+		t.Errorf("validate() defaults = %+v", a)
+	}
+}
@@ -0,0 +1,109 @@
+package crypto
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/config"
+	"pulse/internal/retry"
+)
+
+// chartResponse is the part of /coins/{id}/market_chart pulse reads:
+// [timestamp ms, price] pairs, oldest first.
+type chartResponse struct {
+	Prices [][2]float64 `json:"prices"`
+}
+
+const (
+	chartWorkers = 4               // histories fetched at once
+	chartRetry   = 5 * time.Minute // wait after a failed chart before asking again
+)
+
+// chartsCmd fetches the price histories of ids in vs over the last days.
+// Charts are a nicety: one failing doesn't fail the others.
+func chartsCmd(cfg config.Config, ids []string, vs string, days int, round bool) tea.Cmd {
+	return func() tea.Msg {
+		charts := make([][]float64, len(ids))
+		sem := make(chan struct{}, chartWorkers)
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				charts[i], _ = fetchChart(cfg, id, vs, days)
+			}()
+		}
+		wg.Wait()
+
+		msg := ChartsMsg{Charts: map[string][]float64{}, Round: round}
+		for i, id := range ids {
+			msg.Charts[id] = charts[i]
+		}
+		return msg
+	}
+}
+
+// fetchChart returns the coin's prices in vs over the last days.
+func fetchChart(cfg config.Config, id, vs string, days int) ([]float64, error) {
+	url := fmt.Sprintf("%s/coins/%s/market_chart?vs_currency=%s&days=%d",
+		cfg.BaseURLs.CoinGecko, id, vs, days)
+	resp, err := cfg.Client().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := retry.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var data chartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+	prices := make([]float64, len(data.Prices))
+	for i, p := range data.Prices {
+		prices[i] = p[1]
+	}
+	return prices, nil
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values in width cells, averaging the points that fall
+// into each cell so the resolution follows the space available.
+func sparkline(values []float64, width int) string {
+	if width < 1 || len(values) < 2 {
+		return ""
+	}
+	width = min(width, len(values))
+	cells := make([]float64, width)
+	for c := range cells {
+		from, to := c*len(values)/width, (c+1)*len(values)/width
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		cells[c] = sum / float64(to-from)
+	}
+
+	lo, hi := cells[0], cells[0]
+	for _, v := range cells {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range cells {
+		i := len(sparkBlocks) / 2
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currency is how amounts in one of CoinGecko's vs currencies are written.
+type currency struct {
+	symbol   string
+	decimals int // shown for amounts of 1 and more
+}
+
+// currencies are the vs currencies with a symbol of their own. Others are
+// written with their upper-cased code, e.g. "PLN 1,234".
+var currencies = map[string]currency{
+	"usd":  {symbol: "$"},
+	"eur":  {symbol: "€"},
+	"gbp":  {symbol: "£"},
+	"jpy":  {symbol: "¥"},
+	"cny":  {symbol: "CN¥"},
+	"krw":  {symbol: "₩"},
+	"inr":  {symbol: "₹"},
+	"try":  {symbol: "₺"},
+	"rub":  {symbol: "₽"},
+	"uah":  {symbol: "₴"},
+	"ils":  {symbol: "₪"},
+	"ngn":  {symbol: "₦"},
+	"php":  {symbol: "₱"},
+	"vnd":  {symbol: "₫"},
+	"thb":  {symbol: "฿"},
+	"brl":  {symbol: "R$"},
+	"cad":  {symbol: "CA$"},
+	"aud":  {symbol: "A$"},
+	"nzd":  {symbol: "NZ$"},
+	"hkd":  {symbol: "HK$"},
+	"sgd":  {symbol: "S$"},
+	"mxn":  {symbol: "MX$"},
+	"chf":  {symbol: "CHF "},
+	"btc":  {symbol: "₿", decimals: 4},
+	"eth":  {symbol: "Ξ", decimals: 4},
+	"sats": {symbol: "sats "},
+}
+
+func currencyOf(code string) currency {
+	if c, ok := currencies[code]; ok {
+		return c
+	}
+	return currency{symbol: strings.ToUpper(code) + " "}
+}
+
+// formatPrice writes an amount with the currency's symbol and thousands
+// separators. Amounts below 1 keep four significant digits, so small coins
+// quoted in BTC don't round to zero.
+func formatPrice(price float64, code string) string {
+	c := currencyOf(code)
+	decimals := c.decimals
+	if price > 0 && price < 1 {
+		decimals = 4
+		if price < 0.001 {
+			decimals = 3 - int(math.Floor(math.Log10(price)))
+		}
+	}
+	s := strconv.FormatFloat(price, 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	if frac != "" {
+		frac = "." + frac
+	}
+	return c.symbol + group(whole) + frac
+}
+
+// group adds commas between thousands.
+func group(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// formatCompact writes large amounts such as market caps, e.g. "€1.2T".
+func formatCompact(n float64, code string) string {
+	sym := currencyOf(code).symbol
+	switch {
+	case n >= 1e12:
+		return fmt.Sprintf("%s%.1fT", sym, n/1e12)
+	case n >= 1e9:
+		return fmt.Sprintf("%s%.1fB", sym, n/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%s%.1fM", sym, n/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%s%.0fK", sym, n/1e3)
+	default:
+		return fmt.Sprintf("%s%.0f", sym, n)
+	}
+}
+
+// formatSigned writes an amount with an explicit sign.
+func formatSigned(v float64, code string) string {
+	if v < 0 {
+		return "-" + formatPrice(-v, code)
+	}
+	return "+" + formatPrice(v, code)
+}
@@ -0,0 +1,65 @@
+package crypto
+
+import "testing"
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		price float64
+		code  string
+		want  string
+	}{
+		{67234.56, "usd", "$67,235"},
+		{999, "eur", "€999"},
+		{1234567, "jpy", "¥1,234,567"},
+		{0.5, "usd", "$0.5000"},
+		{0.012345, "usd", "$0.0123"},
+		{0.00012347, "usd", "$0.0001235"},
+		{0.000001234, "btc", "₿0.000001234"},
+		{1.23456, "btc", "₿1.2346"},
+		{0, "usd", "$0"},
+		{1500, "pln", "PLN 1,500"},
+		{42, "chf", "CHF 42"},
+	}
+	for _, tt := range tests {
+		if got := formatPrice(tt.price, tt.code); got != tt.want {
+			t.Errorf("formatPrice(%v, %q) = %q, want %q", tt.price, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCompact(t *testing.T) {
+	tests := []struct {
+		n    float64
+		code string
+		want string
+	}{
+		{1.32e12, "usd", "$1.3T"},
+		{45.6e9, "eur", "€45.6B"},
+		{7.89e6, "usd", "$7.9M"},
+		{12345, "usd", "$12K"},
+		{999, "usd", "$999"},
+		{2e9, "sek", "SEK 2.0B"},
+	}
+	for _, tt := range tests {
+		if got := formatCompact(tt.n, tt.code); got != tt.want {
+			t.Errorf("formatCompact(%v, %q) = %q, want %q", tt.n, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatSigned(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{1200, "+$1,200"},
+		{-1200, "-$1,200"},
+		{-0.25, "-$0.2500"},
+		{0, "+$0"},
+	}
+	for _, tt := range tests {
+		if got := formatSigned(tt.v, "usd"); got != tt.want {
+			t.Errorf("formatSigned(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -3,39 +3,40 @@ package crypto
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pulse/internal/config"
+	"pulse/internal/retry"
 )
 
-var symbolMap = map[string]string{
-	"bitcoin":  "BTC",
-	"ethereum": "ETH",
-	"solana":   "SOL",
-	"dogecoin": "DOGE",
-	"cardano":  "ADA",
-	"polkadot": "DOT",
-	"ripple":   "XRP",
-}
-
-func FetchCmd(cfg config.Config) tea.Cmd {
+// FetchCmd resolves the configured coins through the index and fetches
+// their prices in the configured currencies. Market data is in the first
+// currency.
+func FetchCmd(cfg config.Config, index *coinIndex) tea.Cmd {
 	return func() tea.Msg {
-		ids := strings.Join(cfg.CryptoCoins, ",")
+		listed, notes := index.resolve(cfg, cfg.CryptoCoins)
+		if len(listed) == 0 {
+			return ResponseMsg{Notes: notes}
+		}
+		var ids []string
+		for _, c := range listed {
+			ids = append(ids, c.ID)
+		}
+		vs := cfg.CryptoQuotes[0]
 		url := fmt.Sprintf(
-			"https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=usd&include_24hr_change=true&include_market_cap=true&include_24hr_vol=true",
-			ids,
+			"%s/simple/price?ids=%s&vs_currencies=%s&include_24hr_change=true&include_market_cap=true&include_24hr_vol=true",
+			cfg.BaseURLs.CoinGecko, strings.Join(ids, ","), strings.Join(cfg.CryptoQuotes, ","),
 		)
 
-		resp, err := http.Get(url)
+		resp, err := cfg.Client().Get(url)
 		if err != nil {
 			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != 200 {
-			return ResponseMsg{Error: fmt.Errorf("API returned %d", resp.StatusCode)}
+		if err := retry.CheckResponse(resp); err != nil {
+			return ResponseMsg{Error: err}
 		}
 
 		var data apiResponse
@@ -44,26 +45,41 @@ func FetchCmd(cfg config.Config) tea.Cmd {
 		}
 
 		var coins []CoinData
-		for _, id := range cfg.CryptoCoins {
-			if coin, ok := data[id]; ok {
-				sym := symbolMap[id]
-				if sym == "" {
-					sym = strings.ToUpper(id)
-					if len(sym) > 4 {
-						sym = sym[:4]
+		for _, lc := range listed {
+			coin, ok := data[lc.ID]
+			if !ok {
+				continue
+			}
+			price, ok := coin[vs]
+			if !ok {
+				continue
+			}
+			c := CoinData{
+				ID:        lc.ID,
+				Symbol:    lc.Symbol,
+				Name:      lc.Name,
+				Currency:  vs,
+				Price:     price,
+				Change24h: coin[vs+"_24h_change"],
+				MarketCap: coin[vs+"_market_cap"],
+				Volume24h: coin[vs+"_24h_vol"],
+			}
+			for _, other := range cfg.CryptoQuotes[1:] {
+				if p, ok := coin[other]; ok {
+					if c.Quotes == nil {
+						c.Quotes = map[string]float64{}
 					}
+					c.Quotes[other] = p
 				}
-				coins = append(coins, CoinData{
-					ID:        id,
-					Symbol:    sym,
-					Price:     coin.USD,
-					Change24h: coin.Change24h,
-					MarketCap: coin.MarketCap,
-					Volume24h: coin.Volume24h,
-				})
 			}
+			coins = append(coins, c)
+		}
+		// CoinGecko leaves out currencies it doesn't know rather than
+		// failing the request.
+		if len(coins) == 0 && len(data) > 0 {
+			return ResponseMsg{Error: fmt.Errorf("crypto panel: no prices in %q; is it a CoinGecko vs currency?", vs)}
 		}
 
-		return ResponseMsg{Coins: coins}
+		return ResponseMsg{Coins: coins, Notes: notes}
 	}
 }
@@ -0,0 +1,126 @@
+package crypto
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"pulse/internal/config"
+)
+
+// coinGecko serves the CoinGecko endpoints the panel uses. With down set,
+// the coin list fails.
+func coinGecko(t *testing.T, down bool) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body any
+		switch r.URL.Path {
+		case "/coins/list":
+			if down {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			body = []listedCoin{
+				{ID: "bitcoin", Symbol: "btc", Name: "Bitcoin"},
+				{ID: "ethereum", Symbol: "eth", Name: "Ethereum"},
+				{ID: "uniswap", Symbol: "uni", Name: "Uniswap"},
+				{ID: "universe-token", Symbol: "uni", Name: "Universe"},
+			}
+		case "/coins/markets":
+			body = []marketCap{{ID: "universe-token", MarketCap: 1e5}, {ID: "uniswap", MarketCap: 5e9}}
+		case "/simple/price":
+			data := apiResponse{}
+			for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+				if id == "bitcoin" || id == "uniswap" {
+					data[id] = map[string]float64{"usd": 100, "usd_24h_change": -2, "usd_market_cap": 1e9, "usd_24h_vol": 1e6, "eur": 90}
+				}
+			}
+			body = data
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testConfig(t *testing.T, srv *httptest.Server, coins ...string) config.Config {
+	return config.Config{
+		HTTPClient:   srv.Client(),
+		BaseURLs:     config.BaseURLs{CoinGecko: srv.URL},
+		CacheDir:     t.TempDir(),
+		CryptoCoins:  coins,
+		CryptoQuotes: []string{"usd", "eur"},
+	}
+}
+
+func TestFetchCmd(t *testing.T) {
+	srv := coinGecko(t, false)
+	cfg := testConfig(t, srv, "BTC", "bitcoin", "uni", "dogecoin")
+
+	msg := FetchCmd(cfg, newCoinIndex())().(ResponseMsg)
+	if msg.Error != nil {
+		t.Fatalf("FetchCmd() error: %v", msg.Error)
+	}
+	var ids []string
+	for _, c := range msg.Coins {
+		ids = append(ids, c.ID)
+	}
+	if !slices.Equal(ids, []string{"bitcoin", "uniswap"}) {
+		t.Errorf("coins = %v, want bitcoin and uniswap", ids)
+	}
+	if want := []string{`unknown coin "dogecoin"`}; !slices.Equal(msg.Notes, want) {
+		t.Errorf("Notes = %q, want %q", msg.Notes, want)
+	}
+
+	btc := msg.Coins[0]
+	if btc.Symbol != "BTC" || btc.Currency != "usd" || btc.Price != 100 || btc.Change24h != -2 ||
+		btc.MarketCap != 1e9 || btc.Volume24h != 1e6 || btc.Quotes["eur"] != 90 {
+		t.Errorf("bitcoin = %+v", btc)
+	}
+}
+
+func TestFetchCmdUnknownCurrency(t *testing.T) {
+	srv := coinGecko(t, false)
+	cfg := testConfig(t, srv, "bitcoin")
+	cfg.CryptoQuotes = []string{"xyz"}
+
+	msg := FetchCmd(cfg, newCoinIndex())().(ResponseMsg)
+	if msg.Error == nil || !strings.Contains(msg.Error.Error(), `"xyz"`) {
+		t.Errorf("FetchCmd() error = %v, want one naming the currency", msg.Error)
+	}
+}
+
+func TestResolveWithoutCoinList(t *testing.T) {
+	srv := coinGecko(t, true)
+	cfg := testConfig(t, srv)
+
+	coins, notes := newCoinIndex().resolve(cfg, []string{"btc", "Bitcoin", "my-coin"})
+	want := []listedCoin{knownCoins[0], {ID: "my-coin", Symbol: "MY-COIN"}}
+	if !slices.Equal(coins, want) {
+		t.Errorf("resolve() = %+v, want %+v", coins, want)
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "coin list unavailable") {
+		t.Errorf("notes = %q, want the coin list noted as unavailable", notes)
+	}
+}
+
+func TestResolveCachesCoinList(t *testing.T) {
+	srv := coinGecko(t, false)
+	cfg := testConfig(t, srv)
+	if coins, _ := newCoinIndex().resolve(cfg, []string{"eth"}); len(coins) != 1 {
+		t.Fatalf("resolve() = %+v, want ethereum", coins)
+	}
+
+	// A fresh index reads the list from the cache while the API is down.
+	srv.Close()
+	coins, notes := newCoinIndex().resolve(cfg, []string{"eth"})
+	if len(coins) != 1 || coins[0].ID != "ethereum" || len(notes) != 0 {
+		t.Errorf("resolve() = %+v, %q; want ethereum from the cache", coins, notes)
+	}
+}
@@ -0,0 +1,255 @@
+package crypto
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pulse/internal/cache"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/retry"
+)
+
+// listedCoin is an entry of CoinGecko's /coins/list.
+type listedCoin struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+const (
+	coinListCache = "coingecko-coins" // cache entry shared by all crypto panels
+	coinListTTL   = 24 * time.Hour
+	coinListRetry = 5 * time.Minute
+)
+
+// coinIndex resolves configured coins, given as ids, symbols or names, to
+// CoinGecko ids with their tickers and names. The coin list is kept on disk
+// for a day. It lives behind a pointer so copies of the panel share it, and
+// is only used from fetches.
+type coinIndex struct {
+	mu       sync.Mutex
+	byID     map[string]listedCoin
+	bySymbol map[string][]string // lower-cased symbol → ids
+	byName   map[string][]string // lower-cased name → ids
+	loaded   time.Time
+	failed   time.Time           // last failed download, to back off
+	picks    map[string]resolved // entry → coin, so shared symbols are ranked once
+}
+
+type resolved struct {
+	coin        listedCoin
+	note        string // set when the pick was a close call
+	provisional bool   // picked without market caps; not kept in picks
+}
+
+func newCoinIndex() *coinIndex {
+	return &coinIndex{picks: map[string]resolved{}}
+}
+
+// knownCoins stand in for the coin list while it can't be had, so the usual
+// coins keep their tickers and can still be configured by ticker.
+var knownCoins = []listedCoin{
+	{ID: "bitcoin", Symbol: "BTC", Name: "Bitcoin"},
+	{ID: "ethereum", Symbol: "ETH", Name: "Ethereum"},
+	{ID: "solana", Symbol: "SOL", Name: "Solana"},
+	{ID: "dogecoin", Symbol: "DOGE", Name: "Dogecoin"},
+	{ID: "cardano", Symbol: "ADA", Name: "Cardano"},
+	{ID: "polkadot", Symbol: "DOT", Name: "Polkadot"},
+	{ID: "ripple", Symbol: "XRP", Name: "XRP"},
+}
+
+// resolve maps entries to coins, dropping duplicates, and returns notes on
+// entries that are unknown or were ambiguous. Without the coin list, entries
+// are looked up in knownCoins or else taken to be ids.
+func (x *coinIndex) resolve(cfg config.Config, entries []string) ([]listedCoin, []string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if !x.load(cfg) {
+		var coins []listedCoin
+		for _, e := range entries {
+			c := listedCoin{ID: strings.ToLower(e), Symbol: strings.ToUpper(e)}
+			if i := slices.IndexFunc(knownCoins, func(k listedCoin) bool {
+				return strings.EqualFold(e, k.ID) || strings.EqualFold(e, k.Symbol) || strings.EqualFold(e, k.Name)
+			}); i >= 0 {
+				c = knownCoins[i]
+			}
+			if !slices.ContainsFunc(coins, func(have listedCoin) bool { return have.ID == c.ID }) {
+				coins = append(coins, c)
+			}
+		}
+		return coins, []string{"coin list unavailable; only ids and common tickers resolve"}
+	}
+
+	var coins []listedCoin
+	var notes []string
+	for _, e := range entries {
+		r, ok := x.picks[e]
+		if !ok {
+			if r, ok = x.lookup(cfg, e); !ok {
+				notes = append(notes, fmt.Sprintf("unknown coin %q", e))
+				continue
+			}
+			if !r.provisional {
+				x.picks[e] = r
+			}
+		}
+		if r.note != "" {
+			notes = append(notes, r.note)
+		}
+		if !slices.ContainsFunc(coins, func(c listedCoin) bool { return c.ID == r.coin.ID }) {
+			coins = append(coins, r.coin)
+		}
+	}
+	return coins, notes
+}
+
+// lookup finds the coin an entry names: an id first, then a symbol, then a
+// name. A symbol or name shared by several coins goes to the largest by
+// market cap; a runner-up within a tenth of it is worth a note.
+func (x *coinIndex) lookup(cfg config.Config, entry string) (resolved, bool) {
+	key := strings.ToLower(entry)
+	if c, ok := x.byID[key]; ok {
+		return resolved{coin: c}, true
+	}
+	ids := x.bySymbol[key]
+	if len(ids) == 0 {
+		ids = x.byName[key]
+	}
+	switch len(ids) {
+	case 0:
+		return resolved{}, false
+	case 1:
+		return resolved{coin: x.byID[ids[0]]}, true
+	}
+
+	ranked, err := fetchMarketCaps(cfg, ids)
+	if err != nil {
+		// Tried again next time.
+		return resolved{
+			coin: x.byID[ids[0]],
+			note: fmt.Sprintf("%s matches %d coins, showing %s unranked (%s); use an id to pick another",
+				entry, len(ids), ids[0], err),
+			provisional: true,
+		}, true
+	}
+	// Coins without market data are all alike.
+	if len(ranked) == 0 {
+		ranked = []marketCap{{ID: ids[0]}, {ID: ids[1]}}
+	}
+	r := resolved{coin: x.byID[ranked[0].ID]}
+	if len(ranked) > 1 && ranked[1].MarketCap >= ranked[0].MarketCap/10 {
+		r.note = fmt.Sprintf("%s matches %d coins, showing %s; use an id to pick another",
+			entry, len(ids), r.coin.ID)
+	}
+	return r, true
+}
+
+// load fills the index from the disk cache or, when that is missing or a
+// day old, from /coins/list, and reports whether there is a list to go by.
+// A stale list is better than none when the download fails, which is
+// retried after a few minutes.
+func (x *coinIndex) load(cfg config.Config) bool {
+	if time.Since(x.loaded) < coinListTTL {
+		return true
+	}
+	if time.Since(x.failed) < coinListRetry {
+		return x.byID != nil
+	}
+
+	store := cache.Store{Dir: panel.Or(cfg.CacheDir, cache.DefaultDir())}
+	var list []listedCoin
+	data, saved, err := store.Load(coinListCache)
+	if err == nil {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil || time.Since(saved) >= coinListTTL {
+		fresh, fetchErr := fetchCoinList(cfg)
+		switch {
+		case fetchErr == nil:
+			list, saved = fresh, time.Now()
+			// Failing to cache only costs a download next time.
+			store.Save(coinListCache, list)
+		case err != nil:
+			x.failed = time.Now()
+			return x.byID != nil
+		default:
+			x.failed = time.Now()
+		}
+	}
+
+	x.byID = make(map[string]listedCoin, len(list))
+	x.bySymbol = map[string][]string{}
+	x.byName = map[string][]string{}
+	for _, c := range list {
+		c.Symbol = strings.ToUpper(c.Symbol)
+		sym, name := strings.ToLower(c.Symbol), strings.ToLower(c.Name)
+		x.byID[c.ID] = c
+		x.bySymbol[sym] = append(x.bySymbol[sym], c.ID)
+		x.byName[name] = append(x.byName[name], c.ID)
+	}
+	x.loaded = saved
+	clear(x.picks)
+	return true
+}
+
+func fetchCoinList(cfg config.Config) ([]listedCoin, error) {
+	resp, err := cfg.Client().Get(cfg.BaseURLs.CoinGecko + "/coins/list")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := retry.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var list []listedCoin
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// marketCap is the part of /coins/markets used to rank coins sharing a
+// symbol.
+type marketCap struct {
+	ID        string  `json:"id"`
+	MarketCap float64 `json:"market_cap"`
+}
+
+// fetchMarketCaps returns the coins with market data among ids, largest
+// first.
+func fetchMarketCaps(cfg config.Config, ids []string) ([]marketCap, error) {
+	u := fmt.Sprintf("%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=250&ids=%s",
+		cfg.BaseURLs.CoinGecko, strings.Join(ids, ","))
+	resp, err := cfg.Client().Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := retry.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var caps []marketCap
+	if err := json.NewDecoder(resp.Body).Decode(&caps); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(caps, func(a, b marketCap) int {
+		switch {
+		case a.MarketCap > b.MarketCap:
+			return -1
+		case a.MarketCap < b.MarketCap:
+			return 1
+		}
+		return 0
+	})
+	return caps, nil
+}
@@ -1,62 +1,215 @@
 package crypto
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 	"pulse/internal/config"
+	"pulse/internal/panel"
 	"pulse/internal/style"
 )
 
 type Model struct {
-	config      config.Config
-	coins       []CoinData
-	lastUpdated time.Time
-	loading     bool
-	err         error
-	spinner     spinner.Model
-}
-
-func New(cfg config.Config) Model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = style.AccentStyle
-	return Model{
-		config:  cfg,
-		loading: true,
-		spinner: s,
-	}
+	config   config.Config
+	title    string
+	coins    []CoinData
+	alerts   Alerts
+	state    *alertState
+	holdings map[string]Holding
+	index    *coinIndex
+	notes    []string
+	health   panel.Health
+	spinner  spinner.Model
+
+	sparkDays  int // history the sparklines cover; 0 hides them
+	sparkEvery time.Duration
+	charts     map[string][]float64
+	chartsAt   time.Time // last full round of chart fetches
+	charting   bool      // a chart fetch is in flight
+	chartsFail time.Time // last fetch with a failed chart, to back off
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(30*time.Second, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+type options struct {
+	Coins            []string           `yaml:"coins"`
+	Currencies       []string           `yaml:"currencies"`
+	Alerts           Alerts             `yaml:"alerts"`
+	Sparkline        string             `yaml:"sparkline"`
+	SparklineRefresh time.Duration      `yaml:"sparkline_refresh"`
+	Holdings         map[string]Holding `yaml:"holdings"`
+	Portfolio        string             `yaml:"portfolio"` // YAML file of holdings
+}
+
+// Definition registers the crypto panel type.
+var Definition = panel.Definition{
+	Name:   "crypto",
+	Toggle: "c",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{
+		Coins:            cfg.CryptoCoins,
+		Currencies:       cfg.CryptoQuotes,
+		Alerts:           Alerts{Hysteresis: 1},
+		Sparkline:        "24h",
+		SparklineRefresh: 15 * time.Minute,
+	}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	days, ok := map[string]int{"24h": 1, "7d": 7, "off": 0}[opts.Sparkline]
+	if !ok {
+		return Model{}, fmt.Errorf("crypto panel: sparkline must be 24h, 7d or off, not %q", opts.Sparkline)
+	}
+	if err := opts.Alerts.validate(); err != nil {
+		return Model{}, err
+	}
+	held, err := holdings(opts)
+	if err != nil {
+		return Model{}, err
+	}
+	var quotes []string
+	for _, c := range opts.Currencies {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			quotes = append(quotes, c)
+		}
+	}
+	if len(quotes) == 0 {
+		return Model{}, fmt.Errorf("crypto panel: currencies can't be empty")
+	}
+	cfg.CryptoCoins = opts.Coins
+	cfg.CryptoQuotes = quotes
+
+	s := panel.NewSpinner()
+	return Model{
+		config:   cfg,
+		title:    panel.Or(pc.Title, "Crypto"),
+		alerts:   opts.Alerts,
+		state:    newAlertState(),
+		holdings: held,
+		index:    newCoinIndex(),
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 30*time.Second),
+			Loading:  true,
+		},
+		spinner:    s,
+		sparkDays:  days,
+		sparkEvery: opts.SparklineRefresh,
+	}, nil
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+	return m.spinner.Tick
+}
+
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+// Fetch gets the prices. Price history follows once they are in; see
+// chartsDue.
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config, m.index)
+}
+
+// chartsDue starts fetching price history on the slower sparkline
+// schedule, or straight away for coins without a chart yet, unless a chart
+// failed within chartRetry.
+func (m Model) chartsDue() (Model, tea.Cmd) {
+	if m.sparkDays == 0 || m.charting || time.Since(m.chartsFail) < chartRetry {
+		return m, nil
+	}
+	round := time.Since(m.chartsAt) >= m.sparkEvery
+	var ids []string
+	for _, c := range m.coins {
+		if _, ok := m.charts[c.ID]; round || !ok {
+			ids = append(ids, c.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return m, nil
+	}
+	m.charting = true
+	return m, chartsCmd(m.config, ids, m.config.CryptoQuotes[0], m.sparkDays, round)
 }
 
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+func (m Model) Snapshot() any { return m.coins }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	var coins []CoinData
+	if err := json.Unmarshal(data, &coins); err != nil {
+		return m, err
+	}
+	// Prices cached before quotes were configurable are in dollars.
+	for _, c := range coins {
+		if vs := panel.Or(c.Currency, "usd"); vs != m.config.CryptoQuotes[0] {
+			return m, fmt.Errorf("crypto panel: cached prices are in %s", vs)
+		}
+	}
+	m.coins = coins
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case ResponseMsg:
-		m.loading = false
 		if msg.Error != nil {
-			m.err = msg.Error
+			m.health.Fail(msg.Error)
 		} else {
 			m.coins = msg.Coins
-			m.err = nil
-			m.lastUpdated = time.Now()
+			m.notes = msg.Notes
+			if len(m.coins) > 0 {
+				for _, k := range unheld(m.holdings, m.coins) {
+					m.notes = append(m.notes, fmt.Sprintf("holding for %q matches no coin", k))
+				}
+			}
+			m.health.Succeed()
+			fired := m.state.check(m.alerts, m.coins, time.Now())
+			var charts tea.Cmd
+			m, charts = m.chartsDue()
+			return m, tea.Batch(alertCmd(m.alerts, fired), charts)
 		}
-		return m, tickCmd()
+		return m, nil
 
-	case TickMsg:
-		m.loading = true
-		return m, tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+	case ChartsMsg:
+		m.charting = false
+		charts := maps.Clone(m.charts)
+		if charts == nil {
+			charts = map[string][]float64{}
+		}
+		// A failed chart keeps the coin's last one and holds off the next
+		// fetch rather than retrying with every price update.
+		for id, c := range msg.Charts {
+			if c != nil {
+				charts[id] = c
+			} else {
+				m.chartsFail = time.Now()
+			}
+		}
+		m.charts = charts
+		// A round that got nothing, e.g. while offline, is retried once the
+		// backoff has passed.
+		for _, c := range msg.Charts {
+			if msg.Round && c != nil {
+				m.chartsAt = time.Now()
+				break
+			}
+		}
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -67,80 +220,254 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	return m, nil
+}
+
+func (m Model) Actions() []panel.Action {
+	return []panel.Action{
+		{Name: "Add coin", Prompt: "CoinGecko id or symbol, e.g. AVAX", Refresh: true},
+		{Name: "Remove coin", Prompt: "CoinGecko id or symbol"},
+		{Name: "Clear alert log"},
+	}
+}
+
+func (m Model) RunAction(name, arg string) (panel.Panel, error) {
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "Add coin":
+		if m.shows(arg) {
+			return m, fmt.Errorf("%s is already shown", arg)
+		}
+		m.config.CryptoCoins = append(slices.Clone(m.config.CryptoCoins), arg)
+	case "Remove coin":
+		// Entries may be ids, symbols or names; drop the one naming the coin.
+		i := slices.IndexFunc(m.coins, func(c CoinData) bool {
+			return strings.EqualFold(arg, c.ID) || strings.EqualFold(arg, c.Symbol)
+		})
+		names := []string{arg}
+		if i >= 0 {
+			names = append(names, m.coins[i].ID, m.coins[i].Symbol, m.coins[i].Name)
+		}
+		j := slices.IndexFunc(m.config.CryptoCoins, func(e string) bool {
+			return slices.ContainsFunc(names, func(n string) bool { return n != "" && strings.EqualFold(e, n) })
+		})
+		if j < 0 {
+			return m, fmt.Errorf("%s is not shown", arg)
+		}
+		if len(m.config.CryptoCoins) == 1 {
+			return m, fmt.Errorf("can't remove the last coin")
+		}
+		m.config.CryptoCoins = slices.Delete(slices.Clone(m.config.CryptoCoins), j, j+1)
+		if i >= 0 {
+			m.coins = slices.Delete(slices.Clone(m.coins), i, i+1)
+		}
+	case "Clear alert log":
+		m.state.log = nil
+	}
+	return m, nil
+}
+
+// shows reports whether a coin given by id or symbol is configured or on
+// screen.
+func (m Model) shows(coin string) bool {
+	return slices.ContainsFunc(m.config.CryptoCoins, func(e string) bool { return strings.EqualFold(e, coin) }) ||
+		slices.ContainsFunc(m.coins, func(c CoinData) bool {
+			return strings.EqualFold(coin, c.ID) || strings.EqualFold(coin, c.Symbol)
+		})
+}
+
+func (m Model) SelectedURL() string {
+	return ""
+}
+
+func (m Model) Summary() []panel.Line {
+	var lines []panel.Line
+	pf := valuePortfolio(m.coins, m.holdings)
+	for _, coin := range m.coins {
+		text := fmt.Sprintf("%s %s %+.1f%%", coin.Symbol, formatPrice(coin.Price, coin.Currency), coin.Change24h)
+		for _, q := range m.quotes(coin) {
+			text += " · " + q
+		}
+		text += fmt.Sprintf(" (MCap %s · Vol %s)",
+			formatCompact(coin.MarketCap, coin.Currency), formatCompact(coin.Volume24h, coin.Currency))
+		if p, ok := pf.positions[coin.ID]; ok {
+			text += " · " + positionText(p, pf.currency)
+		}
+		lines = append(lines, panel.Line{Text: text})
+	}
+	if len(pf.positions) > 0 {
+		lines = append(lines, panel.Line{Text: "Portfolio " + portfolioText(pf)})
+	}
+	for _, n := range m.notes {
+		lines = append(lines, panel.Line{Text: "Note: " + n})
+	}
+	return lines
+}
+
+// quotes formats the coin's price in the other configured currencies, in
+// config order.
+func (m Model) quotes(coin CoinData) []string {
+	var out []string
+	for _, vs := range m.config.CryptoQuotes[1:] {
+		if p, ok := coin.Quotes[vs]; ok {
+			out = append(out, formatPrice(p, vs))
+		}
+	}
+	return out
+}
+
+// positionText describes a position, e.g.
+// "0.5 = $48,500 · 42% · P&L +$18,500 (+61.7%)".
+func positionText(p position, vs string) string {
+	text := fmt.Sprintf("%s = %s · %.0f%%", formatQuantity(p.Quantity), formatPrice(p.Value, vs), p.Share)
+	if p.Cost > 0 {
+		text += " · P&L " + p.PnL().format(vs)
+	}
+	return text
+}
+
+// portfolioText describes the portfolio's total, e.g.
+// "$115,300 · 24h +$2,040 (+1.8%) · P&L +$20,000 (+21.0%)".
+func portfolioText(pf portfolio) string {
+	text := fmt.Sprintf("%s · 24h %s", formatPrice(pf.value, pf.currency), pf.Change().format(pf.currency))
+	if pf.cost > 0 {
+		text += " · P&L " + pf.PnL().format(pf.currency)
+	}
+	return text
+}
+
 func (m Model) View(width, height int) string {
-	title := style.TitleStyle.Render("📈 Crypto")
+	title := style.TitleStyle.Render("📈 " + m.title)
 
-	if m.loading && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n  %s Loading prices...", title, m.spinner.View())
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Loading prices...", title, panel.SpinnerView(m.spinner))
 	}
 
-	if m.err != nil && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.err.Error()))
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
 	}
 
 	var lines []string
 	lines = append(lines, title)
 	lines = append(lines, "")
 
-	for _, coin := range m.coins {
-		price := formatPrice(coin.Price)
-		change := fmt.Sprintf("%+.1f%%", coin.Change24h)
-
+	// Rows are padded to a common width so the sparklines line up.
+	rows := make([]string, len(m.coins))
+	rowWidth := 0
+	now := time.Now()
+	for i, coin := range m.coins {
 		changeStyle := style.PositiveStyle
 		if coin.Change24h < 0 {
 			changeStyle = style.NegativeStyle
 		}
+		symbol := style.BoldText.Render(coin.Symbol)
+		if m.state.flashing(coin.ID, now) {
+			symbol = style.WarningStyle.Reverse(true).Render(coin.Symbol)
+		}
+		rows[i] = fmt.Sprintf("  %s  %s  %s",
+			symbol,
+			formatPrice(coin.Price, coin.Currency),
+			changeStyle.Render(fmt.Sprintf("%+.1f%%", coin.Change24h)),
+		)
+		if quotes := m.quotes(coin); len(quotes) > 0 {
+			rows[i] += "  " + style.SubtitleStyle.Render(strings.Join(quotes, " · "))
+		}
+		rowWidth = max(rowWidth, lipgloss.Width(rows[i]))
+	}
+
+	pf := valuePortfolio(m.coins, m.holdings)
+	for i, coin := range m.coins {
+		row := rows[i]
+		if spark := m.sparkline(coin.ID, width-rowWidth-2); spark != "" {
+			row += strings.Repeat(" ", rowWidth-lipgloss.Width(row)+2) + spark
+		}
+		lines = append(lines, row)
+
+		// Held coins show the position instead of market data
+		if p, ok := pf.positions[coin.ID]; ok {
+			lines = append(lines, "     "+positionView(p, pf.currency))
+			continue
+		}
+		detail := fmt.Sprintf("MCap %s · Vol %s",
+			formatCompact(coin.MarketCap, coin.Currency),
+			formatCompact(coin.Volume24h, coin.Currency))
+		if coin.Name != "" {
+			detail = coin.Name + " · " + detail
+		}
+		lines = append(lines, "     "+style.SubtitleStyle.Render(runewidth.Truncate(detail, width-5, "…")))
+	}
 
+	if len(m.notes) > 0 {
+		lines = append(lines, "")
+		for _, n := range m.notes {
+			lines = append(lines, style.WarningStyle.Render(runewidth.Truncate("  "+n, width, "…")))
+		}
+	}
+
+	if len(pf.positions) > 0 {
+		day := pf.Change()
+		lines = append(lines, "")
 		lines = append(lines, fmt.Sprintf("  %s  %s  %s",
-			style.BoldWhite.Render(coin.Symbol),
-			price,
-			changeStyle.Render(change),
-		))
-		lines = append(lines, fmt.Sprintf("     %s",
-			style.SubtitleStyle.Render(fmt.Sprintf("MCap %s · Vol %s",
-				formatCompact(coin.MarketCap),
-				formatCompact(coin.Volume24h))),
+			style.BoldText.Render("Total"),
+			formatPrice(pf.value, pf.currency),
+			signStyle(day.Abs).Render(day.format(pf.currency)+" 24h"),
 		))
+		if pf.cost > 0 {
+			pnl := pf.PnL()
+			lines = append(lines, fmt.Sprintf("     %s %s",
+				style.SubtitleStyle.Render("P&L"),
+				signStyle(pnl.Abs).Render(pnl.format(pf.currency)),
+			))
+		}
+	}
+
+	// Latest alerts, as many as fit
+	if room := height - len(lines) - 3; room > 0 && len(m.state.log) > 0 {
+		lines = append(lines, "")
+		log := m.state.log[max(len(m.state.log)-min(room, 3), 0):]
+		for i := len(log) - 1; i >= 0; i-- {
+			a := log[i]
+			lines = append(lines, style.WarningStyle.Render(runewidth.Truncate(
+				fmt.Sprintf("  🔔 %s %s", a.At.Format("15:04"), a.Text), width, "…")))
+		}
 	}
 
 	lines = append(lines, "")
-	updated := m.lastUpdated.Format("15:04:05")
-	lines = append(lines, style.SubtitleStyle.Render(fmt.Sprintf("  Updated %s", updated)))
+	lines = append(lines, "  "+m.health.Footer(width-2))
 
 	return strings.Join(lines, "\n")
 }
 
-func formatCompact(n float64) string {
-	switch {
-	case n >= 1e12:
-		return fmt.Sprintf("$%.1fT", n/1e12)
-	case n >= 1e9:
-		return fmt.Sprintf("$%.1fB", n/1e9)
-	case n >= 1e6:
-		return fmt.Sprintf("$%.1fM", n/1e6)
-	case n >= 1e3:
-		return fmt.Sprintf("$%.0fK", n/1e3)
-	default:
-		return fmt.Sprintf("$%.0f", n)
-	}
-}
-
-func formatPrice(price float64) string {
-	if price >= 1 {
-		whole := int64(price + 0.5)
-		s := fmt.Sprintf("%d", whole)
-		// Add commas
-		if len(s) > 3 {
-			var result []byte
-			for i, c := range s {
-				if i > 0 && (len(s)-i)%3 == 0 {
-					result = append(result, ',')
-				}
-				result = append(result, byte(c))
-			}
-			return "$" + string(result)
-		}
-		return "$" + s
+// positionView renders a position's detail line: quantity, value,
+// allocation and, with a cost basis, P&L.
+func positionView(p position, vs string) string {
+	line := style.SubtitleStyle.Render(fmt.Sprintf("%s = %s · %.0f%%",
+		formatQuantity(p.Quantity), formatPrice(p.Value, vs), p.Share))
+	if p.Cost > 0 {
+		pnl := p.PnL()
+		line += style.SubtitleStyle.Render(" · P&L ") + signStyle(pnl.Abs).Render(pnl.format(vs))
+	}
+	return line
+}
+
+func signStyle(v float64) lipgloss.Style {
+	if v < 0 {
+		return style.NegativeStyle
+	}
+	return style.PositiveStyle
+}
+
+// sparkline draws the coin's price history in up to width cells, green
+// when it ends higher than it started and red otherwise.
+func (m Model) sparkline(id string, width int) string {
+	values := m.charts[id]
+	if width < 8 || len(values) < 2 {
+		return ""
+	}
+	s := style.PositiveStyle
+	if values[len(values)-1] < values[0] {
+		s = style.NegativeStyle
 	}
-	return fmt.Sprintf("$%.4f", price)
+	return s.Render(sparkline(values, width))
 }
@@ -0,0 +1,57 @@
+package crypto
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"pulse/internal/config"
+)
+
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	m, err := New(config.Config{CryptoCoins: []string{"bitcoin", "ethereum"}, CryptoQuotes: []string{"usd"}}, config.PanelConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.coins = []CoinData{{ID: "bitcoin"}, {ID: "ethereum"}}
+	return m
+}
+
+func TestChartsMsg(t *testing.T) {
+	m := newTestModel(t)
+	m.charts = map[string][]float64{"bitcoin": {1, 2, 3}}
+	m.charting = true
+
+	p, _ := m.Update(ChartsMsg{Charts: map[string][]float64{"bitcoin": nil, "ethereum": {4, 5}}, Round: true})
+	m = p.(Model)
+	if m.charting {
+		t.Error("charting still set after the charts came in")
+	}
+	if !slices.Equal(m.charts["bitcoin"], []float64{1, 2, 3}) {
+		t.Errorf("bitcoin chart = %v, want the last one kept", m.charts["bitcoin"])
+	}
+	if !slices.Equal(m.charts["ethereum"], []float64{4, 5}) {
+		t.Errorf("ethereum chart = %v", m.charts["ethereum"])
+	}
+	if time.Since(m.chartsAt) > time.Second {
+		t.Error("a round with a chart didn't advance the schedule")
+	}
+}
+
+func TestChartsBackoff(t *testing.T) {
+	m := newTestModel(t)
+	m.sparkDays = 7
+	m.sparkEvery = time.Hour
+
+	p, _ := m.Update(ChartsMsg{Charts: map[string][]float64{"bitcoin": {1, 2}, "ethereum": nil}})
+	m = p.(Model)
+	if _, cmd := m.chartsDue(); cmd != nil {
+		t.Error("charts fetched again straight after a failure")
+	}
+
+	m.chartsFail = time.Now().Add(-chartRetry)
+	if _, cmd := m.chartsDue(); cmd == nil {
+		t.Error("charts not fetched once the backoff passed")
+	}
+}
@@ -0,0 +1,176 @@
+package crypto
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Holding is a position in one coin. Cost is the total paid for it, used
+// for profit and loss; zero leaves P&L out.
+type Holding struct {
+	Quantity float64 `yaml:"quantity"`
+	Cost     float64 `yaml:"cost"`
+}
+
+// loadPortfolio reads holdings keyed by coin id from a YAML file.
+func loadPortfolio(path string) (map[string]Holding, error) {
+	data, err := os.ReadFile(os.ExpandEnv(path))
+	if err != nil {
+		return nil, fmt.Errorf("crypto panel: portfolio: %w", err)
+	}
+	var h map[string]Holding
+	if err := yaml.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("crypto panel: portfolio: %s: %w", path, err)
+	}
+	return h, nil
+}
+
+// holdings merges the holdings from the config with those from the
+// portfolio file, which win. They are keyed by coin id or symbol.
+func holdings(opts options) (map[string]Holding, error) {
+	all := map[string]Holding{}
+	for id, h := range opts.Holdings {
+		all[id] = h
+	}
+	if opts.Portfolio != "" {
+		file, err := loadPortfolio(opts.Portfolio)
+		if err != nil {
+			return nil, err
+		}
+		for id, h := range file {
+			all[id] = h
+		}
+	}
+	for id, h := range all {
+		if h.Quantity < 0 || h.Cost < 0 {
+			return nil, fmt.Errorf("crypto panel: holding for %q can't be negative", id)
+		}
+	}
+	return all, nil
+}
+
+// position is a holding valued at the current price.
+type position struct {
+	Holding
+	Value    float64
+	Previous float64 // value at the price 24h ago
+	Share    float64 // percent of the portfolio's value
+}
+
+// PnL is the unrealised profit or loss against the cost.
+func (p position) PnL() change {
+	return changeFrom(p.Value, p.Cost)
+}
+
+// portfolio values the holdings at the coins' current prices, in their
+// quote currency.
+type portfolio struct {
+	positions map[string]position
+	currency  string
+	value     float64
+	previous  float64 // value at the prices 24h ago
+	cost      float64
+	costed    float64 // current value of the positions with a cost
+}
+
+func valuePortfolio(coins []CoinData, holdings map[string]Holding) portfolio {
+	pf := portfolio{positions: map[string]position{}}
+	for _, c := range coins {
+		h, ok := holdingFor(holdings, c)
+		if !ok {
+			continue
+		}
+		pf.currency = c.Currency
+		p := position{Holding: h, Value: h.Quantity * c.Price}
+		// A coin down 100% had some value a day ago that the change can't
+		// tell; count it as unchanged rather than divide by zero.
+		p.Previous = p.Value
+		if c.Change24h > -100 {
+			p.Previous = p.Value / (1 + c.Change24h/100)
+		}
+		pf.positions[c.ID] = p
+		pf.value += p.Value
+		pf.previous += p.Previous
+		if p.Cost > 0 {
+			pf.cost += p.Cost
+			pf.costed += p.Value
+		}
+	}
+	for id, p := range pf.positions {
+		if pf.value > 0 {
+			p.Share = p.Value / pf.value * 100
+		}
+		pf.positions[id] = p
+	}
+	return pf
+}
+
+// holdingFor finds the holding for coin by id or, failing that, symbol.
+func holdingFor(holdings map[string]Holding, coin CoinData) (Holding, bool) {
+	if h, ok := holdings[coin.ID]; ok {
+		return h, true
+	}
+	for k, h := range holdings {
+		if strings.EqualFold(k, coin.Symbol) {
+			return h, true
+		}
+	}
+	return Holding{}, false
+}
+
+// unheld returns the holdings that match none of coins, sorted.
+func unheld(holdings map[string]Holding, coins []CoinData) []string {
+	var out []string
+	for k := range holdings {
+		if !slices.ContainsFunc(coins, func(c CoinData) bool {
+			return k == c.ID || strings.EqualFold(k, c.Symbol)
+		}) {
+			out = append(out, k)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Change is the portfolio's value change over 24h.
+func (pf portfolio) Change() change {
+	return changeFrom(pf.value, pf.previous)
+}
+
+// PnL is the unrealised profit or loss of the positions with a cost.
+func (pf portfolio) PnL() change {
+	return changeFrom(pf.costed, pf.cost)
+}
+
+// change is a difference from a base value, with its percentage when the
+// base isn't zero.
+type change struct {
+	Abs    float64
+	Pct    float64
+	HasPct bool
+}
+
+func changeFrom(value, base float64) change {
+	if base == 0 {
+		return change{Abs: value}
+	}
+	return change{Abs: value - base, Pct: (value/base - 1) * 100, HasPct: true}
+}
+
+// format writes the change as "+$1,200 (+3.4%)", or just "+$1,200" when
+// there is no percentage.
+func (c change) format(vs string) string {
+	if !c.HasPct {
+		return formatSigned(c.Abs, vs)
+	}
+	return fmt.Sprintf("%s (%+.1f%%)", formatSigned(c.Abs, vs), c.Pct)
+}
+
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
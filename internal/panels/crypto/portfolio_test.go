@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestChangeFrom(t *testing.T) {
+	tests := []struct {
+		value, base float64
+		want        change
+		text        string
+	}{
+		{1200, 1000, change{Abs: 200, Pct: 20, HasPct: true}, "+$200 (+20.0%)"},
+		{750, 1000, change{Abs: -250, Pct: -25, HasPct: true}, "-$250 (-25.0%)"},
+		{1000, 1000, change{Abs: 0, Pct: 0, HasPct: true}, "+$0 (+0.0%)"},
+		{500, 0, change{Abs: 500}, "+$500"},
+		{0, 0, change{}, "+$0"},
+	}
+	for _, tt := range tests {
+		got := changeFrom(tt.value, tt.base)
+		if got.HasPct != tt.want.HasPct || math.Abs(got.Abs-tt.want.Abs) > 1e-9 || math.Abs(got.Pct-tt.want.Pct) > 1e-9 {
+			t.Errorf("changeFrom(%v, %v) = %+v, want %+v", tt.value, tt.base, got, tt.want)
+		}
+		if s := got.format("usd"); s != tt.text {
+			t.Errorf("changeFrom(%v, %v).format() = %q, want %q", tt.value, tt.base, s, tt.text)
+		}
+	}
+}
+
+func TestValuePortfolio(t *testing.T) {
+	coins := []CoinData{
+		{ID: "bitcoin", Symbol: "BTC", Currency: "usd", Price: 60000, Change24h: 20},
+		{ID: "ethereum", Symbol: "ETH", Currency: "usd", Price: 3000, Change24h: -100},
+		{ID: "solana", Symbol: "SOL", Currency: "usd", Price: 100},
+	}
+	holdings := map[string]Holding{
+		"bitcoin": {Quantity: 0.5, Cost: 25000},
+		"eth":     {Quantity: 10},
+		"cardano": {Quantity: 1000},
+	}
+	pf := valuePortfolio(coins, holdings)
+
+	if pf.currency != "usd" || len(pf.positions) != 2 {
+		t.Fatalf("valuePortfolio() = %+v, want bitcoin and ethereum in usd", pf)
+	}
+	btc, eth := pf.positions["bitcoin"], pf.positions["ethereum"]
+	if btc.Value != 30000 || btc.Previous != 25000 || btc.Share != 50 {
+		t.Errorf("bitcoin = %+v", btc)
+	}
+	// A 100% drop is counted as unchanged rather than dividing by zero.
+	if eth.Value != 30000 || eth.Previous != 30000 {
+		t.Errorf("ethereum = %+v", eth)
+	}
+
+	if c := pf.Change(); c.Abs != 5000 || !c.HasPct {
+		t.Errorf("Change() = %+v, want +5000", c)
+	}
+	// Only bitcoin has a cost.
+	if c := pf.PnL(); c.format("usd") != "+$5,000 (+20.0%)" {
+		t.Errorf("PnL() = %+v, want +5000 (+20%%)", c)
+	}
+	if c := eth.PnL(); c.HasPct || c.Abs != 30000 {
+		t.Errorf("ethereum PnL() = %+v, want no percentage", c)
+	}
+
+	if got := unheld(holdings, coins); !slices.Equal(got, []string{"cardano"}) {
+		t.Errorf("unheld() = %v, want [cardano]", got)
+	}
+}
@@ -1,26 +1,39 @@
 package crypto
 
-import "time"
-
-type apiResponse map[string]struct {
-	USD       float64 `json:"usd"`
-	Change24h float64 `json:"usd_24h_change"`
-	MarketCap float64 `json:"usd_market_cap"`
-	Volume24h float64 `json:"usd_24h_vol"`
-}
+// apiResponse maps coin ids to fields named after each vs currency:
+// "eur", "eur_24h_change", "eur_market_cap" and "eur_24h_vol".
+type apiResponse map[string]map[string]float64
 
+// CoinData is a coin quoted in the primary currency, with its price in any
+// other configured currencies in Quotes.
 type CoinData struct {
 	ID        string
 	Symbol    string
+	Name      string `json:",omitempty"`
+	Currency  string
 	Price     float64
 	Change24h float64
 	MarketCap float64
 	Volume24h float64
+	Quotes    map[string]float64 `json:",omitempty"`
 }
 
 type ResponseMsg struct {
 	Coins []CoinData
+	// Notes are about configured coins that are unknown or ambiguous.
+	Notes []string
 	Error error
 }
 
-type TickMsg time.Time
+func (m ResponseMsg) FetchError() error { return m.Error }
+
+// ChartsMsg carries price histories, fetched after the prices so they
+// never hold them up. A coin whose chart failed maps to nil.
+type ChartsMsg struct {
+	Charts map[string][]float64
+	Round  bool // a full round on the sparkline schedule
+}
+
+// Background lets the panel clear its in-flight flag even if it was hidden
+// while the charts were being fetched.
+func (ChartsMsg) Background() {}
@@ -0,0 +1,238 @@
+package feed
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/config"
+	"pulse/internal/retry"
+)
+
+// FetchCmd reads every feed concurrently and merges their entries, newest
+// first. Feeds that fail are reported in Failed; the fetch only fails as a
+// whole when none could be read.
+func FetchCmd(cfg config.Config, feeds []Source, limit int) tea.Cmd {
+	return func() tea.Msg {
+		type result struct {
+			entries []Entry
+			err     error
+		}
+		results := make([]result, len(feeds))
+		var wg sync.WaitGroup
+		for i, src := range feeds {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				entries, err := fetchFeed(cfg, src)
+				results[i] = result{entries, err}
+			}()
+		}
+		wg.Wait()
+
+		var all []Entry
+		var failed []string
+		var firstErr error
+		for i, r := range results {
+			if r.err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", sourceName(feeds[i], ""), r.err))
+				firstErr = cmp.Or(firstErr, r.err)
+				continue
+			}
+			all = append(all, r.entries...)
+		}
+		if len(failed) == len(feeds) {
+			return ResponseMsg{Error: firstErr}
+		}
+
+		return ResponseMsg{Entries: merge(all, limit), Failed: failed}
+	}
+}
+
+func fetchFeed(cfg config.Config, src Source) ([]Entry, error) {
+	req, err := http.NewRequest("GET", src.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := cfg.Client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := retry.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return parse(resp.Body, src)
+}
+
+// parse decodes an RSS or Atom document into entries attributed to src.
+func parse(r io.Reader, src Source) ([]Entry, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charsetReader
+	dec.Strict = false
+
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+
+	var entries []Entry
+	switch doc.XMLName.Local {
+	case "rss", "RDF":
+		name := sourceName(src, doc.Channel.Title)
+		for _, it := range append(doc.Channel.Items, doc.Items...) {
+			entries = append(entries, Entry{
+				ID:        strings.TrimSpace(it.GUID),
+				Title:     clean(it.Title),
+				URL:       strings.TrimSpace(it.Link),
+				Source:    name,
+				Published: parseDate(cmp.Or(it.PubDate, it.Date)),
+			})
+		}
+	case "feed":
+		name := sourceName(src, doc.Title)
+		for _, e := range doc.Entries {
+			entries = append(entries, Entry{
+				ID:        strings.TrimSpace(e.ID),
+				Title:     clean(e.Title),
+				URL:       atomLinkHref(e.Links),
+				Source:    name,
+				Published: parseDate(cmp.Or(e.Published, e.Updated)),
+			})
+		}
+	default:
+		return nil, fmt.Errorf("decode failed: <%s> is not an RSS or Atom feed", doc.XMLName.Local)
+	}
+	return entries, nil
+}
+
+// merge sorts entries newest first, drops duplicates (the same link or id
+// seen in several feeds or twice in one) and keeps at most limit.
+func merge(entries []Entry, limit int) []Entry {
+	slices.SortStableFunc(entries, func(a, b Entry) int {
+		return b.Published.Compare(a.Published)
+	})
+
+	seen := map[string]bool{}
+	var out []Entry
+	for _, e := range entries {
+		if e.Title == "" {
+			continue
+		}
+		key := cmp.Or(e.URL, e.ID, e.Source+"\x00"+e.Title)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, e)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+// sourceName prefers the configured name, then the feed's own title, then
+// the host it was fetched from.
+func sourceName(src Source, title string) string {
+	if name := cmp.Or(src.Name, clean(title)); name != "" {
+		return name
+	}
+	if u, err := url.Parse(src.URL); err == nil && u.Host != "" {
+		return strings.TrimPrefix(u.Hostname(), "www.")
+	}
+	return src.URL
+}
+
+// atomLinkHref picks the entry's alternate link, which is the default when
+// rel is omitted.
+func atomLinkHref(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+	if len(links) > 0 {
+		return strings.TrimSpace(links[0].Href)
+	}
+	return ""
+}
+
+// clean collapses whitespace and decodes entities left in titles that were
+// double-escaped by the publisher.
+func clean(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+var dateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 2 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// charsetReader handles the legacy single-byte encodings some older feeds
+// still declare; encoding/xml itself only reads UTF-8. windows-1252 is read
+// as Latin-1, which differs only in a few punctuation marks.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "latin1", "windows-1252":
+		return latin1Reader{bufio.NewReader(input)}, nil
+	}
+	return nil, errors.New("unsupported charset " + charset)
+}
+
+type latin1Reader struct {
+	r *bufio.Reader
+}
+
+func (l latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n+1 < len(p) {
+		b, err := l.r.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b < 0x80 {
+			p[n] = b
+			n++
+		} else {
+			n += copy(p[n:], string(rune(b)))
+		}
+	}
+	return n, nil
+}
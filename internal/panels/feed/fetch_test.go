@@ -0,0 +1,197 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"pulse/internal/config"
+)
+
+const rss2 = `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+  <title>Example  Blog</title>
+  <item>
+    <title>First &amp;amp; foremost</title>
+    <link> https://example.com/1 </link>
+    <guid>1</guid>
+    <pubDate>Tue, 03 Jun 2025 09:39:21 GMT</pubDate>
+  </item>
+  <item><title>Undated</title><link>https://example.com/2</link></item>
+</channel></rss>`
+
+const rdf = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel><title>RDF feed</title></channel>
+  <item><title>Dated</title><link>https://example.org/a</link><dc:date>2025-06-03T09:39:21Z</dc:date></item>
+</rdf:RDF>`
+
+const atom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Atom feed</title>
+  <entry>
+    <title>Entry</title>
+    <link rel="self" href="https://example.net/self"/>
+    <link href="https://example.net/entry"/>
+    <id>urn:1</id>
+    <updated>2025-06-03T09:39:21Z</updated>
+  </entry>
+</feed>`
+
+const latin1 = "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+	"<rss><channel><title>Caf\xe9</title><item><title>Cr\xe8me</title></item></channel></rss>"
+
+func TestParse(t *testing.T) {
+	published := time.Date(2025, 6, 3, 9, 39, 21, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		doc  string
+		src  Source
+		want []Entry
+	}{
+		{"rss 2.0", rss2, Source{URL: "https://example.com/feed"}, []Entry{
+			{ID: "1", Title: "First & foremost", URL: "https://example.com/1", Source: "Example Blog", Published: published},
+			{Title: "Undated", URL: "https://example.com/2", Source: "Example Blog"},
+		}},
+		{"rss 1.0", rdf, Source{URL: "https://example.org/rdf"}, []Entry{
+			{Title: "Dated", URL: "https://example.org/a", Source: "RDF feed", Published: published},
+		}},
+		{"atom", atom, Source{URL: "https://example.net/atom", Name: "Mine"}, []Entry{
+			{ID: "urn:1", Title: "Entry", URL: "https://example.net/entry", Source: "Mine", Published: published},
+		}},
+		{"latin-1", latin1, Source{URL: "https://example.fr/rss"}, []Entry{
+			{Title: "Crème", Source: "Café"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse(strings.NewReader(tt.doc), tt.src)
+			if err != nil {
+				t.Fatalf("parse() error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parse() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if !g.Published.Equal(w.Published) {
+					t.Errorf("entry %d published %v, want %v", i, g.Published, w.Published)
+				}
+				g.Published, w.Published = time.Time{}, time.Time{}
+				if g != w {
+					t.Errorf("entry %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, doc := range []string{"", "<html><body>not a feed</body></html>", "{}"} {
+		if _, err := parse(strings.NewReader(doc), Source{}); err == nil {
+			t.Errorf("parse(%q) succeeded", doc)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	entries := []Entry{
+		{Title: "old", URL: "u1", Published: day(1)},
+		{Title: "new", URL: "u2", Published: day(3)},
+		{Title: "same link elsewhere", URL: "u2", Published: day(2)},
+		{Title: "undated", ID: "id", Source: "a"},
+		{Title: "", URL: "u3", Published: day(4)},
+		{Title: "no link", Source: "a", Published: day(2)},
+		{Title: "no link", Source: "a", Published: day(2)},
+		{Title: "no link", Source: "b", Published: day(2)},
+	}
+
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{0, []string{"new", "no link", "no link", "old", "undated"}},
+		{2, []string{"new", "no link"}},
+	}
+	for _, tt := range tests {
+		got := merge(append([]Entry(nil), entries...), tt.limit)
+		var titles []string
+		for _, e := range got {
+			titles = append(titles, e.Title)
+		}
+		if strings.Join(titles, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("merge(limit %d) = %q, want %q", tt.limit, titles, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"Tue, 03 Jun 2025 09:39:21 +0000", time.Date(2025, 6, 3, 9, 39, 21, 0, time.UTC)},
+		{" 2025-06-03T09:39:21Z ", time.Date(2025, 6, 3, 9, 39, 21, 0, time.UTC)},
+		{"Tue, 3 Jun 2025 09:39 +0000", time.Date(2025, 6, 3, 9, 39, 0, 0, time.UTC)},
+		{"2025-06-03", time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseDate(tt.in); !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	tests := []struct {
+		src   Source
+		title string
+		want  string
+	}{
+		{Source{URL: "https://www.example.com/rss", Name: "Mine"}, "Theirs", "Mine"},
+		{Source{URL: "https://www.example.com/rss"}, " Theirs ", "Theirs"},
+		{Source{URL: "https://www.example.com/rss"}, "", "example.com"},
+		{Source{URL: "feed.xml"}, "", "feed.xml"},
+	}
+	for _, tt := range tests {
+		if got := sourceName(tt.src, tt.title); got != tt.want {
+			t.Errorf("sourceName(%+v, %q) = %q, want %q", tt.src, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestFetchCmd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss":
+			w.Write([]byte(rss2))
+		case "/atom":
+			w.Write([]byte(atom))
+		default:
+			http.Error(w, "gone", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	cfg := config.Config{HTTPClient: srv.Client()}
+
+	msg := FetchCmd(cfg, []Source{{URL: srv.URL + "/rss"}, {URL: srv.URL + "/atom"}, {URL: srv.URL + "/broken", Name: "Broken"}}, 10)().(ResponseMsg)
+	if msg.Error != nil {
+		t.Fatalf("FetchCmd() error: %v", msg.Error)
+	}
+	if len(msg.Entries) != 3 || msg.Entries[2].Title != "Undated" {
+		t.Errorf("Entries = %+v, want 3 with the undated one last", msg.Entries)
+	}
+	if len(msg.Failed) != 1 || !strings.HasPrefix(msg.Failed[0], "Broken: ") {
+		t.Errorf("Failed = %q, want the broken feed", msg.Failed)
+	}
+
+	msg = FetchCmd(cfg, []Source{{URL: srv.URL + "/broken"}}, 10)().(ResponseMsg)
+	if msg.Error == nil {
+		t.Error("FetchCmd() with every feed failing succeeded")
+	}
+}
@@ -0,0 +1,240 @@
+package feed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/style"
+)
+
+// Model merges entries from one or more RSS or Atom feeds into a single
+// list, newest first.
+type Model struct {
+	config  config.Config
+	opts    options
+	title   string
+	entries []Entry
+	failed  []string
+	list    panel.List
+	health  panel.Health
+	spinner spinner.Model
+}
+
+// Definition registers the feed panel type.
+var Definition = panel.Definition{
+	Name:   "feed",
+	Toggle: "f",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+	ConfigOnly: true,
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{Limit: 30}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	if len(opts.Feeds) == 0 {
+		return Model{}, errors.New("feed panel: feeds is required")
+	}
+	for _, f := range opts.Feeds {
+		if f.URL == "" {
+			return Model{}, errors.New("feed panel: every feed needs a url")
+		}
+	}
+
+	s := panel.NewSpinner()
+	return Model{
+		config: cfg,
+		opts:   opts,
+		title:  panel.Or(pc.Title, "Feeds"),
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 15*time.Minute),
+			Loading:  true,
+		},
+		spinner: s,
+	}, nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config, m.opts.Feeds, m.opts.Limit)
+}
+
+func (m Model) Snapshot() any { return m.entries }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.entries); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResponseMsg:
+		if msg.Error != nil {
+			m.health.Fail(msg.Error)
+		} else {
+			m.entries = msg.Entries
+			m.failed = msg.Failed
+			m.health.Succeed()
+			m.list.Clamp(entryList(m.entries))
+		}
+		return m, nil
+
+	case panel.SizeMsg:
+		m.list.Resize(m.listRows(msg.Height), entryList(m.entries))
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	m.list.HandleKey(msg, entryList(m.entries))
+	return m, nil
+}
+
+func (m Model) Captures(msg tea.KeyMsg) bool { return m.list.Captures(msg) }
+
+func (m Model) KeyBindings() []key.Binding {
+	return panel.Keys.ListBindings()
+}
+
+// entryList adapts entries to panel.Items. Each takes a title and a source
+// line; the filter matches title and source.
+type entryList []Entry
+
+func (l entryList) Len() int     { return len(l) }
+func (l entryList) Rows(int) int { return 2 }
+func (l entryList) Fields(i int) []string {
+	return []string{l[i].Title, l[i].Source}
+}
+
+// listRows is the room for entries at the given height, leaving the title,
+// the scroll indicators, the failed feeds warning and the footer.
+func (m Model) listRows(height int) int {
+	if len(m.failed) > 0 {
+		return height - 5
+	}
+	return height - 4
+}
+
+func (m Model) SelectAt(row, height int) (panel.Panel, bool) {
+	ok := m.list.SelectRow(row-2, entryList(m.entries), m.listRows(height))
+	return m, ok
+}
+
+func (m Model) Scroll(delta int) panel.Panel {
+	m.list.Scroll(delta, entryList(m.entries))
+	return m
+}
+
+func (m Model) SelectedURL() string {
+	i, ok := m.list.Item(entryList(m.entries))
+	if !ok {
+		return ""
+	}
+	return m.entries[i].URL
+}
+
+func (m Model) Summary() []panel.Line {
+	var lines []panel.Line
+	for _, e := range m.entries {
+		text := fmt.Sprintf("%s (%s", e.Title, e.Source)
+		if !e.Published.IsZero() {
+			text += ", " + e.Published.Local().Format("2006-01-02 15:04")
+		}
+		lines = append(lines, panel.Line{Text: text + ")", URL: e.URL})
+	}
+	return lines
+}
+
+func (m Model) View(width, height int) string {
+	title := style.TitleStyle.Render("📡 " + m.title)
+
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Loading feeds...", title, panel.SpinnerView(m.spinner))
+	}
+
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
+	}
+
+	page := m.list.Page(entryList(m.entries), m.listRows(height))
+	filter := m.list.Filter
+
+	var lines []string
+	lines = append(lines, title+filter.Status(page.Matches, len(m.entries)))
+	lines = append(lines, page.MoreAbove())
+
+	maxTitle := width - 8
+	if maxTitle < 20 {
+		maxTitle = 20
+	}
+
+	for _, i := range page.Items {
+		e := m.entries[i]
+
+		cursor := "  "
+		if i == page.Selected {
+			cursor = style.AccentStyle.Render("▸ ")
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s %s",
+			cursor,
+			style.AccentStyle.Render(fmt.Sprintf("%d.", i+1)),
+			filter.Highlight(runewidth.Truncate(e.Title, maxTitle, "..."), lipgloss.NewStyle()),
+		))
+
+		meta := filter.Highlight(runewidth.Truncate(e.Source, maxTitle-3, "..."), style.SubtitleStyle)
+		if !e.Published.IsZero() {
+			meta += style.SubtitleStyle.Render(" · " + panel.FormatAge(time.Since(e.Published)))
+		}
+		lines = append(lines, "     "+meta)
+	}
+	switch {
+	case len(m.entries) == 0:
+		lines = append(lines, style.SubtitleStyle.Render("  No entries"))
+	case len(page.Items) == 0:
+		lines = append(lines, style.SubtitleStyle.Render("  No matches"))
+	}
+
+	lines = append(lines, page.MoreBelow())
+	if len(m.failed) > 0 {
+		msg := fmt.Sprintf("  %d of %d feeds failed: %s", len(m.failed), len(m.opts.Feeds), m.failed[0])
+		lines = append(lines, style.WarningStyle.Render(runewidth.Truncate(msg, width, "...")))
+	}
+	hint := style.SubtitleStyle.Render(panel.Hint())
+	lines = append(lines, hint+m.health.Footer(width-lipgloss.Width(hint)))
+
+	return strings.Join(lines, "\n")
+}
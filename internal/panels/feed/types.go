@@ -0,0 +1,80 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Entry struct {
+	ID        string
+	Title     string
+	URL       string
+	Source    string
+	Published time.Time // zero when the feed gives no date
+}
+
+// Source is one feed to follow. In the config file it is either a bare URL
+// or a mapping with url and an optional display name.
+type Source struct {
+	URL  string `yaml:"url"`
+	Name string `yaml:"name"`
+}
+
+func (s *Source) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&s.URL)
+	}
+	type plain Source
+	return node.Decode((*plain)(s))
+}
+
+type options struct {
+	Feeds []Source `yaml:"feeds"`
+	Limit int      `yaml:"limit"`
+}
+
+type ResponseMsg struct {
+	Entries []Entry
+	Failed  []string // "source: error" for feeds that couldn't be read
+	Error   error
+}
+
+func (m ResponseMsg) FetchError() error { return m.Error }
+
+// document covers RSS 2.0 (<rss><channel>), RSS 1.0 (<rdf:RDF>, items
+// beside the channel) and Atom (<feed>); only the fields for the root
+// actually found are filled.
+type document struct {
+	XMLName xml.Name
+	Channel struct {
+		Title string    `xml:"title"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items []rssItem `xml:"item"`
+
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title   string `xml:"title"`
+	Link    string `xml:"link"`
+	GUID    string `xml:"guid"`
+	PubDate string `xml:"pubDate"`
+	Date    string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomEntry struct {
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	ID        string     `xml:"id"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
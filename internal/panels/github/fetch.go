@@ -9,14 +9,20 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pulse/internal/config"
+	"pulse/internal/retry"
 )
 
-const eventCount = 8
+// eventCount is how many events are fetched unless the panel's count option
+// says otherwise; maxEventCount is the most GitHub returns in one page.
+const (
+	eventCount    = 8
+	maxEventCount = 100
+)
 
-func FetchCmd(cfg config.Config) tea.Cmd {
+func FetchCmd(cfg config.Config, count int) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf("https://api.github.com/users/%s/events?per_page=%d",
-			cfg.GitHubUser, eventCount)
+		url := fmt.Sprintf("%s/users/%s/events?per_page=%d",
+			cfg.BaseURLs.GitHub, cfg.GitHubUser, count)
 
 		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
@@ -28,14 +34,14 @@ func FetchCmd(cfg config.Config) tea.Cmd {
 			req.Header.Set("Authorization", "Bearer "+cfg.GitHubToken)
 		}
 
-		resp, err := http.DefaultClient.Do(req)
+		resp, err := cfg.Client().Do(req)
 		if err != nil {
 			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != 200 {
-			return ResponseMsg{Error: fmt.Errorf("API returned %d", resp.StatusCode)}
+		if err := retry.CheckResponse(resp); err != nil {
+			return ResponseMsg{Error: err}
 		}
 
 		var apiEvents []apiEvent
@@ -57,7 +63,8 @@ func FetchCmd(cfg config.Config) tea.Cmd {
 			})
 		}
 
-		return ResponseMsg{Events: events}
+		reset, _ := retry.RateLimitReset(resp.Header)
+		return ResponseMsg{Events: events, RateLimitReset: reset}
 	}
 }
 
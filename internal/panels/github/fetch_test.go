@@ -0,0 +1,94 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pulse/internal/config"
+	"pulse/internal/retry"
+)
+
+const events = `[
+  {"type": "PushEvent", "repo": {"name": "octo/hello"}, "created_at": "2025-06-03T09:39:21Z",
+   "payload": {"commits": [{"message": "Fix typo\n\nLong body"}, {"message": "Second"}]}},
+  {"type": "PullRequestEvent", "repo": {"name": "octo/hello"}, "created_at": "2025-06-02T09:39:21Z",
+   "payload": {"action": "closed", "pull_request": {"title": "Add docs", "html_url": "https://github.com/octo/hello/pull/7", "merged": true}}},
+  {"type": "CreateEvent", "repo": {"name": "octo/new"}, "created_at": "not a date",
+   "payload": {"ref_type": "repository"}}
+]`
+
+func TestFetchCmd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/octo/events" || r.URL.Query().Get("per_page") != "3" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "4102444800")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "4102444800")
+		w.Write([]byte(events))
+	}))
+	defer srv.Close()
+	cfg := config.Config{
+		HTTPClient:  srv.Client(),
+		BaseURLs:    config.BaseURLs{GitHub: srv.URL},
+		GitHubUser:  "octo",
+		GitHubToken: "token",
+	}
+
+	msg := FetchCmd(cfg, 3)().(ResponseMsg)
+	if msg.Error != nil {
+		t.Fatalf("FetchCmd() error: %v", msg.Error)
+	}
+	want := []Event{
+		{Type: "PushEvent", Repo: "octo/hello", Action: "Pushed to", Detail: "2 commits · Fix typo",
+			URL: "https://github.com/octo/hello", Created: time.Date(2025, 6, 3, 9, 39, 21, 0, time.UTC)},
+		{Type: "PullRequestEvent", Repo: "octo/hello", Action: "PR merged on", Detail: "Add docs",
+			URL: "https://github.com/octo/hello/pull/7", Created: time.Date(2025, 6, 2, 9, 39, 21, 0, time.UTC)},
+		{Type: "CreateEvent", Repo: "octo/new", Action: "Created repo", URL: "https://github.com/octo/new"},
+	}
+	if len(msg.Events) != len(want) {
+		t.Fatalf("Events = %+v, want %+v", msg.Events, want)
+	}
+	for i, e := range msg.Events {
+		if e != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, e, want[i])
+		}
+	}
+	// The last call of the quota still succeeds but holds the next one.
+	if !msg.RateLimitReset.Equal(time.Unix(4102444800, 0)) {
+		t.Errorf("RateLimitReset = %v", msg.RateLimitReset)
+	}
+
+	cfg.GitHubToken = ""
+	msg = FetchCmd(cfg, 3)().(ResponseMsg)
+	var se *retry.StatusError
+	if !errors.As(msg.Error, &se) || !se.RateLimited() {
+		t.Errorf("FetchCmd() without a token = %v, want a rate limit", msg.Error)
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	var e apiEvent
+	e.Type = "ForkEvent"
+	e.Repo.Name = "octo/hello"
+	e.Payload.Forkee.FullName = "me/hello"
+	action, detail, url := formatEvent(e)
+	if action != "Forked" || detail != "→ me/hello" || url != "https://github.com/me/hello" {
+		t.Errorf("formatEvent(fork) = %q, %q, %q", action, detail, url)
+	}
+
+	e = apiEvent{Type: "GollumEvent"}
+	e.Repo.Name = "octo/wiki"
+	if action, _, url := formatEvent(e); action != "GollumEvent" || url != "https://github.com/octo/wiki" {
+		t.Errorf("formatEvent(unknown) = %q, %q", action, url)
+	}
+}
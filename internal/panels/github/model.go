@@ -1,63 +1,117 @@
 package github
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/retry"
 	"pulse/internal/style"
 )
 
 type Model struct {
 	config      config.Config
+	title       string
 	events      []Event
-	selected    int
-	lastUpdated time.Time
-	loading     bool
-	err         error
+	count       int
+	list        panel.List
+	health      panel.Health
+	rateLimited time.Time // no requests before this
 	spinner     spinner.Model
 }
 
-func New(cfg config.Config) Model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = style.AccentStyle
+type options struct {
+	Username string `yaml:"username"`
+	Token    string `yaml:"token"`
+	Count    int    `yaml:"count"`
+}
+
+// Definition registers the github panel type.
+var Definition = panel.Definition{
+	Name:   "github",
+	Toggle: "g",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{Username: cfg.GitHubUser, Token: cfg.GitHubToken, Count: eventCount}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	if opts.Count < 1 || opts.Count > maxEventCount {
+		return Model{}, fmt.Errorf("github panel: count must be between 1 and %d", maxEventCount)
+	}
+	cfg.GitHubUser = opts.Username
+	cfg.GitHubToken = opts.Token
+
+	s := panel.NewSpinner()
 	return Model{
-		config:  cfg,
-		loading: true,
+		config: cfg,
+		title:  panel.Or(pc.Title, "GitHub"),
+		count:  opts.Count,
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 5*time.Minute),
+			Loading:  true,
+		},
 		spinner: s,
-	}
+	}, nil
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(5*time.Minute, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
 }
 
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config, m.count)
 }
 
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+func (m Model) Snapshot() any { return m.events }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.events); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case ResponseMsg:
-		m.loading = false
+		m.rateLimited = msg.RateLimitReset
+		var se *retry.StatusError
+		if errors.As(msg.Error, &se) {
+			m.rateLimited = se.RetryAt
+		}
 		if msg.Error != nil {
-			m.err = msg.Error
+			m.health.Fail(msg.Error)
 		} else {
 			m.events = msg.Events
-			m.err = nil
-			m.lastUpdated = time.Now()
+			m.health.Succeed()
+			m.list.Clamp(eventList(m.events))
 		}
-		return m, tickCmd()
+		return m, nil
 
-	case TickMsg:
-		m.loading = true
-		return m, tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+	case panel.SizeMsg:
+		m.list.Resize(m.listRows(msg.Height), eventList(m.events))
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -68,54 +122,104 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) SelectNext() {
-	if len(m.events) > 0 {
-		m.selected = (m.selected + 1) % len(m.events)
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	m.list.HandleKey(msg, eventList(m.events))
+	return m, nil
+}
+
+func (m Model) Captures(msg tea.KeyMsg) bool { return m.list.Captures(msg) }
+
+func (m Model) KeyBindings() []key.Binding {
+	return panel.Keys.ListBindings()
+}
+
+// eventList adapts events to panel.Items. Each takes a line, plus one for
+// its detail; the filter matches repo, action and detail.
+type eventList []Event
+
+func (l eventList) Len() int { return len(l) }
+
+func (l eventList) Rows(i int) int {
+	if l[i].Detail != "" {
+		return 2
 	}
+	return 1
 }
 
-func (m *Model) SelectPrev() {
-	if len(m.events) > 0 {
-		m.selected = (m.selected - 1 + len(m.events)) % len(m.events)
+func (l eventList) Fields(i int) []string {
+	return []string{l[i].Repo, l[i].Action, l[i].Detail}
+}
+
+// listRows is the room for events at the given height, leaving the title,
+// the scroll indicators, the rate limit warning and the footer.
+func (m Model) listRows(height int) int {
+	if time.Until(m.rateLimited) > 0 {
+		return height - 5
 	}
+	return height - 4
+}
+
+func (m Model) SelectAt(row, height int) (panel.Panel, bool) {
+	ok := m.list.SelectRow(row-2, eventList(m.events), m.listRows(height))
+	return m, ok
+}
+
+func (m Model) Scroll(delta int) panel.Panel {
+	m.list.Scroll(delta, eventList(m.events))
+	return m
 }
 
 func (m Model) SelectedURL() string {
-	if len(m.events) == 0 {
+	i, ok := m.list.Item(eventList(m.events))
+	if !ok {
 		return ""
 	}
-	return m.events[m.selected].URL
+	return m.events[i].URL
+}
+
+func (m Model) Summary() []panel.Line {
+	var lines []panel.Line
+	for _, event := range m.events {
+		text := fmt.Sprintf("%s %s", event.Action, event.Repo)
+		if event.Detail != "" {
+			text += ": " + event.Detail
+		}
+		lines = append(lines, panel.Line{
+			Text: fmt.Sprintf("%s (%s)", text, event.Created.Local().Format("2006-01-02 15:04")),
+			URL:  event.URL,
+		})
+	}
+	return lines
 }
 
 func (m Model) View(width, height int) string {
-	title := style.TitleStyle.Render("🐙 GitHub")
+	title := style.TitleStyle.Render("🐙 " + m.title)
 
-	if m.loading && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n  %s Loading activity...", title, m.spinner.View())
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Loading activity...", title, panel.SpinnerView(m.spinner))
 	}
 
-	if m.err != nil && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.err.Error()))
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		msg := m.health.Err.Error()
+		if wait := time.Until(m.rateLimited); wait > 0 {
+			msg += fmt.Sprintf(" · rate limited, retry in %s", formatWait(wait))
+		}
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+msg))
 	}
 
+	page := m.list.Page(eventList(m.events), m.listRows(height))
+	filter := m.list.Filter
+
 	var lines []string
-	lines = append(lines, title)
-	lines = append(lines, "")
+	lines = append(lines, title+filter.Status(page.Matches, len(m.events)))
+	lines = append(lines, page.MoreAbove())
 
 	maxDetail := width - 8
 	if maxDetail < 20 {
 		maxDetail = 20
 	}
 
-	maxEvents := height / 3
-	if maxEvents < 3 {
-		maxEvents = 3
-	}
-	if maxEvents > len(m.events) {
-		maxEvents = len(m.events)
-	}
-
-	for i := 0; i < maxEvents; i++ {
+	for _, i := range page.Items {
 		event := m.events[i]
 		repo := event.Repo
 		if parts := strings.SplitN(repo, "/", 2); len(parts) == 2 {
@@ -123,15 +227,15 @@ func (m Model) View(width, height int) string {
 		}
 
 		cursor := "  "
-		if i == m.selected {
+		if i == page.Selected {
 			cursor = style.AccentStyle.Render("▸ ")
 		}
 
 		lines = append(lines, fmt.Sprintf("%s%s %s %s  %s",
 			cursor,
 			style.AccentStyle.Render("★"),
-			event.Action,
-			style.BoldWhite.Render(repo),
+			filter.Highlight(event.Action, lipgloss.NewStyle()),
+			filter.Highlight(repo, style.BoldText),
 			style.SubtitleStyle.Render(timeAgo(event.Created)),
 		))
 
@@ -141,15 +245,23 @@ func (m Model) View(width, height int) string {
 				d = d[:maxDetail-3] + "..."
 			}
 			lines = append(lines, fmt.Sprintf("     %s",
-				style.SubtitleStyle.Render(d),
+				filter.Highlight(d, style.SubtitleStyle),
 			))
 		}
 	}
 
-	lines = append(lines, "")
-	lines = append(lines, style.SubtitleStyle.Render(
-		fmt.Sprintf("  ↑↓ navigate · o open · Updated %s", m.lastUpdated.Format("15:04")),
-	))
+	if len(page.Items) == 0 && filter.Active() {
+		lines = append(lines, style.SubtitleStyle.Render("  No matches"))
+	}
+
+	lines = append(lines, page.MoreBelow())
+	if wait := time.Until(m.rateLimited); wait > 0 {
+		lines = append(lines, style.WarningStyle.Render(
+			fmt.Sprintf("  rate limited, retry in %s", formatWait(wait)),
+		))
+	}
+	hint := style.SubtitleStyle.Render(panel.Hint())
+	lines = append(lines, hint+m.health.Footer(width-lipgloss.Width(hint)))
 
 	return strings.Join(lines, "\n")
 }
@@ -167,3 +279,14 @@ func timeAgo(t time.Time) string {
 		return fmt.Sprintf("%dd", int(d.Hours()/24))
 	}
 }
+
+func formatWait(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds())+1)
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes())+1)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
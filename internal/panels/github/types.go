@@ -45,6 +45,11 @@ type apiEvent struct {
 type ResponseMsg struct {
 	Events []Event
 	Error  error
+
+	// RateLimitReset is set when the request used up the API quota.
+	RateLimitReset time.Time
 }
 
-type TickMsg time.Time
+func (m ResponseMsg) FetchError() error { return m.Error }
+
+func (m ResponseMsg) NotBefore() time.Time { return m.RateLimitReset }
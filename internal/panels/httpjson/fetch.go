@@ -0,0 +1,94 @@
+package httpjson
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tidwall/gjson"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/retry"
+)
+
+func FetchCmd(cfg config.Config, s spec) tea.Cmd {
+	return func() tea.Msg {
+		req, err := http.NewRequest("GET", s.URL, nil)
+		if err != nil {
+			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
+		}
+
+		req.Header.Set("Accept", "application/json")
+		for k, v := range s.Headers {
+			req.Header.Set(k, os.ExpandEnv(v))
+		}
+
+		resp, err := cfg.Client().Do(req)
+		if err != nil {
+			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
+		}
+		defer resp.Body.Close()
+
+		if err := retry.CheckResponse(resp); err != nil {
+			return ResponseMsg{Error: err}
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return ResponseMsg{Error: fmt.Errorf("read failed: %w", err)}
+		}
+		if !gjson.ValidBytes(body) {
+			return ResponseMsg{Error: fmt.Errorf("decode failed: response is not JSON")}
+		}
+
+		list := gjson.ParseBytes(body)
+		if s.Items != "" {
+			list = list.Get(s.Items)
+			if !list.Exists() {
+				return ResponseMsg{Error: fmt.Errorf("items path %q not found", s.Items)}
+			}
+		}
+
+		var items panel.Entries
+		for _, raw := range list.Array() {
+			if s.Limit > 0 && len(items) >= s.Limit {
+				break
+			}
+			item, err := s.extract(raw)
+			if err != nil {
+				return ResponseMsg{Error: err}
+			}
+			items = append(items, item)
+		}
+
+		return ResponseMsg{Items: items}
+	}
+}
+
+// extract evaluates the field paths against one list element and renders
+// the line templates with the results.
+func (s spec) extract(raw gjson.Result) (panel.Entry, error) {
+	fields := map[string]string{}
+	for name, path := range s.Fields {
+		fields[name] = raw.Get(path).String()
+	}
+
+	var title, detail strings.Builder
+	if err := s.title.Execute(&title, fields); err != nil {
+		return panel.Entry{}, fmt.Errorf("template: %w", err)
+	}
+	if s.detail != nil {
+		if err := s.detail.Execute(&detail, fields); err != nil {
+			return panel.Entry{}, fmt.Errorf("detail template: %w", err)
+		}
+	}
+
+	item := panel.Entry{Title: title.String(), Detail: detail.String()}
+	if s.Link != "" {
+		item.URL = raw.Get(s.Link).String()
+	}
+	return item, nil
+}
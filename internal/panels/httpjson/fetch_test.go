@@ -0,0 +1,95 @@
+package httpjson
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"pulse/internal/config"
+	"pulse/internal/panel"
+)
+
+const releases = `{"data": {"releases": [
+  {"tag": "v1.2.0", "author": {"login": "octo"}, "stars": 12, "html_url": "https://example.com/v1.2.0"},
+  {"tag": "v1.1.0", "author": {"login": "cat"}, "html_url": "https://example.com/v1.1.0"},
+  {"tag": "v1.0.0", "author": {"login": "octo"}, "stars": 3}
+]}}`
+
+func newSpec(t *testing.T, opts options) spec {
+	t.Helper()
+	s := spec{options: opts}
+	var err error
+	if s.title, err = parseTemplate("template", opts.Template); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Detail != "" {
+		if s.detail, err = parseTemplate("detail", opts.Detail); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestFetchCmd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(releases))
+	}))
+	defer srv.Close()
+	t.Setenv("HTTP_TEST_TOKEN", "token")
+	cfg := config.Config{HTTPClient: srv.Client()}
+	base := options{
+		URL:      srv.URL,
+		Headers:  map[string]string{"Authorization": "Bearer $HTTP_TEST_TOKEN"},
+		Items:    "data.releases",
+		Fields:   map[string]string{"tag": "tag", "who": "author.login", "stars": "stars"},
+		Template: "{{.tag}} by {{.who}}",
+		Detail:   "{{.stars}} stars",
+		Link:     "html_url",
+	}
+
+	tests := []struct {
+		name string
+		edit func(*options)
+		want panel.Entries
+		err  string
+	}{
+		{"all", func(*options) {}, panel.Entries{
+			{Title: "v1.2.0 by octo", Detail: "12 stars", URL: "https://example.com/v1.2.0"},
+			{Title: "v1.1.0 by cat", Detail: " stars", URL: "https://example.com/v1.1.0"},
+			{Title: "v1.0.0 by octo", Detail: "3 stars"},
+		}, ""},
+		{"limit", func(o *options) { o.Limit = 2 }, panel.Entries{
+			{Title: "v1.2.0 by octo", Detail: "12 stars", URL: "https://example.com/v1.2.0"},
+			{Title: "v1.1.0 by cat", Detail: " stars", URL: "https://example.com/v1.1.0"},
+		}, ""},
+		{"no detail or link", func(o *options) { o.Detail, o.Link, o.Limit = "", "", 1 }, panel.Entries{
+			{Title: "v1.2.0 by octo"},
+		}, ""},
+		{"missing path", func(o *options) { o.Items = "data.tags" }, nil, `items path "data.tags" not found`},
+		{"status", func(o *options) { o.Headers = nil }, nil, "API returned 401"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := base
+			tt.edit(&opts)
+			msg := FetchCmd(cfg, newSpec(t, opts))().(ResponseMsg)
+			if tt.err != "" {
+				if msg.Error == nil || msg.Error.Error() != tt.err {
+					t.Errorf("error = %v, want %s", msg.Error, tt.err)
+				}
+				return
+			}
+			if msg.Error != nil {
+				t.Fatal(msg.Error)
+			}
+			if !slices.Equal(msg.Items, tt.want) {
+				t.Errorf("items = %+v, want %+v", msg.Items, tt.want)
+			}
+		})
+	}
+}
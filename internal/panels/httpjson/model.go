@@ -0,0 +1,191 @@
+package httpjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+	"pulse/internal/style"
+)
+
+// Model is a config-driven list panel over any JSON endpoint.
+type Model struct {
+	config  config.Config
+	spec    spec
+	title   string
+	items   panel.Entries
+	list    panel.List
+	health  panel.Health
+	spinner spinner.Model
+}
+
+// Definition registers the generic HTTP/JSON panel type.
+var Definition = panel.Definition{
+	Name:   "http",
+	Toggle: "h",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+	ConfigOnly: true,
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	var opts options
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	if opts.URL == "" {
+		return Model{}, errors.New("http panel: url is required")
+	}
+	if opts.Template == "" {
+		return Model{}, errors.New("http panel: template is required")
+	}
+
+	s := spec{options: opts}
+	var err error
+	if s.title, err = parseTemplate("template", opts.Template); err != nil {
+		return Model{}, err
+	}
+	if opts.Detail != "" {
+		if s.detail, err = parseTemplate("detail", opts.Detail); err != nil {
+			return Model{}, err
+		}
+	}
+
+	sp := panel.NewSpinner()
+	return Model{
+		config: cfg,
+		spec:   s,
+		title:  panel.Or(pc.Title, "HTTP"),
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 5*time.Minute),
+			Loading:  true,
+		},
+		spinner: sp,
+	}, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("http panel: %w", err)
+	}
+	return t, nil
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config, m.spec)
+}
+
+func (m Model) Snapshot() any { return m.items }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.items); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ResponseMsg:
+		if msg.Error != nil {
+			m.health.Fail(msg.Error)
+		} else {
+			m.items = msg.Items
+			m.health.Succeed()
+			m.list.Clamp(m.items)
+		}
+		return m, nil
+
+	case panel.SizeMsg:
+		m.list.Resize(listRows(msg.Height), m.items)
+		return m, nil
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	m.list.HandleKey(msg, m.items)
+	return m, nil
+}
+
+func (m Model) Captures(msg tea.KeyMsg) bool { return m.list.Captures(msg) }
+
+func (m Model) KeyBindings() []key.Binding {
+	return panel.Keys.ListBindings()
+}
+
+// listRows is the room for items at the given height, leaving the title,
+// the scroll indicators and the footer.
+func listRows(height int) int { return height - 4 }
+
+func (m Model) SelectAt(row, height int) (panel.Panel, bool) {
+	ok := m.list.SelectRow(row-2, m.items, listRows(height))
+	return m, ok
+}
+
+func (m Model) Scroll(delta int) panel.Panel {
+	m.list.Scroll(delta, m.items)
+	return m
+}
+
+func (m Model) SelectedURL() string {
+	i, ok := m.list.Item(m.items)
+	if !ok {
+		return ""
+	}
+	return m.items[i].URL
+}
+
+func (m Model) Summary() []panel.Line { return m.items.Summary() }
+
+func (m Model) View(width, height int) string {
+	title := style.TitleStyle.Render("🔗 " + m.title)
+
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Loading...", title, panel.SpinnerView(m.spinner))
+	}
+
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
+	}
+
+	page := m.list.Page(m.items, listRows(height))
+
+	var lines []string
+	lines = append(lines, title+m.list.Filter.Status(page.Matches, len(m.items)))
+	lines = append(lines, page.MoreAbove())
+	lines = append(lines, m.items.View(width, page, m.list.Filter)...)
+	lines = append(lines, page.MoreBelow())
+	hint := style.SubtitleStyle.Render(panel.Hint())
+	lines = append(lines, hint+m.health.Footer(width-lipgloss.Width(hint)))
+	return strings.Join(lines, "\n")
+}
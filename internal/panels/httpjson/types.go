@@ -0,0 +1,32 @@
+package httpjson
+
+import (
+	"text/template"
+
+	"pulse/internal/panel"
+)
+
+type options struct {
+	URL      string            `yaml:"url"`
+	Headers  map[string]string `yaml:"headers"`
+	Items    string            `yaml:"items"`    // gjson path to the list
+	Fields   map[string]string `yaml:"fields"`   // template name → gjson path within an item
+	Template string            `yaml:"template"` // first line of each item
+	Detail   string            `yaml:"detail"`   // optional second line
+	Link     string            `yaml:"link"`     // gjson path to the item's URL
+	Limit    int               `yaml:"limit"`
+}
+
+// spec is options compiled for fetching.
+type spec struct {
+	options
+	title  *template.Template
+	detail *template.Template
+}
+
+type ResponseMsg struct {
+	Items panel.Entries
+	Error error
+}
+
+func (m ResponseMsg) FetchError() error { return m.Error }
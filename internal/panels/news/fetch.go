@@ -3,42 +3,68 @@ package news
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/config"
+	"pulse/internal/retry"
 )
 
-const storyCount = 8
+const (
+	// storyCount is how many top stories are fetched unless the panel's
+	// count option says otherwise.
+	storyCount = 8
+	// maxStoryCount is as many as /topstories lists.
+	maxStoryCount = 500
+	// storyWorkers bounds how many items are fetched at once.
+	storyWorkers = 8
+)
 
-func FetchCmd() tea.Cmd {
+// FetchCmd fetches the top count stories. Items are fetched concurrently
+// but keep their rank order.
+func FetchCmd(cfg config.Config, count int) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := http.Get("https://hacker-news.firebaseio.com/v0/topstories.json")
+		resp, err := cfg.Client().Get(cfg.BaseURLs.HackerNews + "/topstories.json")
 		if err != nil {
 			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
 		}
 		defer resp.Body.Close()
 
+		if err := retry.CheckResponse(resp); err != nil {
+			return ResponseMsg{Error: err}
+		}
+
 		var ids []int
 		if err := json.NewDecoder(resp.Body).Decode(&ids); err != nil {
 			return ResponseMsg{Error: fmt.Errorf("decode failed: %w", err)}
 		}
 
-		if len(ids) > storyCount {
-			ids = ids[:storyCount]
+		if len(ids) > count {
+			ids = ids[:count]
 		}
 
-		var stories []Story
-		for _, id := range ids {
-			url := fmt.Sprintf("https://hacker-news.firebaseio.com/v0/item/%d.json", id)
-			r, err := http.Get(url)
-			if err != nil {
-				continue
-			}
-
-			var s apiStory
-			json.NewDecoder(r.Body).Decode(&s)
-			r.Body.Close()
+		results := make([]apiStory, len(ids))
+		sem := make(chan struct{}, storyWorkers)
+		var wg sync.WaitGroup
+		for i, id := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				url := fmt.Sprintf("%s/item/%d.json", cfg.BaseURLs.HackerNews, id)
+				r, err := cfg.Client().Get(url)
+				if err != nil {
+					return
+				}
+				json.NewDecoder(r.Body).Decode(&results[i])
+				r.Body.Close()
+			}()
+		}
+		wg.Wait()
 
+		var stories []Story
+		for _, s := range results {
 			if s.Title == "" {
 				continue
 			}
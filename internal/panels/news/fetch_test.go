@@ -0,0 +1,83 @@
+package news
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pulse/internal/config"
+)
+
+func TestFetchCmd(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/topstories.json" {
+			ids := make([]int, 40)
+			for i := range ids {
+				ids[i] = 100 + i
+			}
+			json.NewEncoder(w).Encode(ids)
+			return
+		}
+		var id int
+		if _, err := fmt.Sscanf(r.URL.Path, "/item/%d.json", &id); err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		// Later ranks answer first, so the order must come from the ids.
+		time.Sleep(time.Duration(140-id) * time.Millisecond / 10)
+		if id == 102 {
+			w.Write([]byte("null")) // deleted story
+			return
+		}
+		json.NewEncoder(w).Encode(apiStory{ID: id, Title: fmt.Sprintf("Story %d", id), Score: id, Descendants: 3})
+	}))
+	defer srv.Close()
+	cfg := config.Config{HTTPClient: srv.Client(), BaseURLs: config.BaseURLs{HackerNews: srv.URL}}
+
+	msg := FetchCmd(cfg, 30)().(ResponseMsg)
+	if msg.Error != nil {
+		t.Fatalf("FetchCmd() error: %v", msg.Error)
+	}
+	var titles []string
+	for _, s := range msg.Stories {
+		titles = append(titles, s.Title)
+	}
+	if len(titles) != 29 || titles[0] != "Story 100" || titles[2] != "Story 103" || titles[28] != "Story 129" {
+		t.Errorf("titles = %q, want stories 100 to 129 in order without 102", titles)
+	}
+	if s := msg.Stories[0]; s.Score != 100 || s.Comments != 3 {
+		t.Errorf("first story = %+v", s)
+	}
+	if peak > storyWorkers {
+		t.Errorf("%d items fetched at once, want at most %d", peak, storyWorkers)
+	}
+}
+
+func TestFetchCmdError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer srv.Close()
+	cfg := config.Config{HTTPClient: srv.Client(), BaseURLs: config.BaseURLs{HackerNews: srv.URL}}
+
+	msg := FetchCmd(cfg, 5)().(ResponseMsg)
+	if msg.Error == nil || !strings.HasPrefix(msg.Error.Error(), "decode failed") {
+		t.Errorf("FetchCmd() error = %v, want a decode failure", msg.Error)
+	}
+}
@@ -1,65 +1,110 @@
 package news
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"pulse/internal/config"
+	"pulse/internal/panel"
 	"pulse/internal/style"
 )
 
 type Model struct {
-	stories     []Story
-	selected    int
-	lastUpdated time.Time
-	loading     bool
-	err         error
-	spinner     spinner.Model
+	config  config.Config
+	title   string
+	stories []Story
+	count   int
+	list    panel.List
+	health  panel.Health
+	spinner spinner.Model
+}
+
+type options struct {
+	Count int `yaml:"count"`
 }
 
 type OpenURLMsg struct {
 	URL string
 }
 
-func New() Model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = style.AccentStyle
+// Definition registers the news panel type.
+var Definition = panel.Definition{
+	Name:   "news",
+	Toggle: "n",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{Count: storyCount}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	if opts.Count < 1 || opts.Count > maxStoryCount {
+		return Model{}, fmt.Errorf("news panel: count must be between 1 and %d", maxStoryCount)
+	}
+
+	s := panel.NewSpinner()
 	return Model{
-		loading: true,
+		config: cfg,
+		title:  panel.Or(pc.Title, "News"),
+		count:  opts.Count,
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 5*time.Minute),
+			Loading:  true,
+		},
 		spinner: s,
-	}
+	}, nil
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(5*time.Minute, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
 }
 
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(FetchCmd(), m.spinner.Tick)
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config, m.count)
+}
+
+func (m Model) Snapshot() any { return m.stories }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.stories); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
 }
 
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case ResponseMsg:
-		m.loading = false
 		if msg.Error != nil {
-			m.err = msg.Error
+			m.health.Fail(msg.Error)
 		} else {
 			m.stories = msg.Stories
-			m.err = nil
-			m.lastUpdated = time.Now()
+			m.health.Succeed()
+			m.list.Clamp(storyList(m.stories))
 		}
-		return m, tickCmd()
+		return m, nil
 
-	case TickMsg:
-		m.loading = true
-		return m, tea.Batch(FetchCmd(), m.spinner.Tick)
+	case panel.SizeMsg:
+		m.list.Resize(listRows(msg.Height), storyList(m.stories))
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -70,58 +115,93 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) SelectNext() {
-	if len(m.stories) > 0 {
-		m.selected = (m.selected + 1) % len(m.stories)
-	}
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	m.list.HandleKey(msg, storyList(m.stories))
+	return m, nil
 }
 
-func (m *Model) SelectPrev() {
-	if len(m.stories) > 0 {
-		m.selected = (m.selected - 1 + len(m.stories)) % len(m.stories)
-	}
+func (m Model) Captures(msg tea.KeyMsg) bool { return m.list.Captures(msg) }
+
+func (m Model) KeyBindings() []key.Binding {
+	return panel.Keys.ListBindings()
+}
+
+// storyList adapts stories to panel.Items. Each takes a title and a detail
+// line; the filter matches title, domain and author.
+type storyList []Story
+
+func (l storyList) Len() int     { return len(l) }
+func (l storyList) Rows(int) int { return 2 }
+func (l storyList) Fields(i int) []string {
+	return []string{l[i].Title, domainFrom(l[i].URL), l[i].By}
+}
+
+// listRows is the room for stories at the given height, leaving the title,
+// the scroll indicators and the footer.
+func listRows(height int) int { return height - 4 }
+
+func (m Model) SelectAt(row, height int) (panel.Panel, bool) {
+	ok := m.list.SelectRow(row-2, storyList(m.stories), listRows(height))
+	return m, ok
+}
+
+func (m Model) Scroll(delta int) panel.Panel {
+	m.list.Scroll(delta, storyList(m.stories))
+	return m
 }
 
 func (m Model) SelectedURL() string {
-	if len(m.stories) == 0 {
+	i, ok := m.list.Item(storyList(m.stories))
+	if !ok {
 		return ""
 	}
-	s := m.stories[m.selected]
+	return storyURL(m.stories[i])
+}
+
+// storyURL links to the story itself, or to its HN discussion for Ask/Show
+// posts without an external URL.
+func storyURL(s Story) string {
 	if s.URL != "" {
 		return s.URL
 	}
 	return fmt.Sprintf("https://news.ycombinator.com/item?id=%d", s.ID)
 }
 
+func (m Model) Summary() []panel.Line {
+	var lines []panel.Line
+	for _, story := range m.stories {
+		lines = append(lines, panel.Line{
+			Text: fmt.Sprintf("%s (%d pts · %d comments)", story.Title, story.Score, story.Comments),
+			URL:  storyURL(story),
+		})
+	}
+	return lines
+}
+
 func (m Model) View(width, height int) string {
-	title := style.TitleStyle.Render("📰 News")
+	title := style.TitleStyle.Render("📰 " + m.title)
 
-	if m.loading && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n  %s Loading stories...", title, m.spinner.View())
+	if m.health.Loading && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n  %s Loading stories...", title, panel.SpinnerView(m.spinner))
 	}
 
-	if m.err != nil && m.lastUpdated.IsZero() {
-		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.err.Error()))
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
 	}
 
+	page := m.list.Page(storyList(m.stories), listRows(height))
+	filter := m.list.Filter
+
 	var lines []string
-	lines = append(lines, title)
-	lines = append(lines, "")
+	lines = append(lines, title+filter.Status(page.Matches, len(m.stories)))
+	lines = append(lines, page.MoreAbove())
 
 	maxTitle := width - 8
 	if maxTitle < 20 {
 		maxTitle = 20
 	}
 
-	maxStories := height / 3
-	if maxStories < 3 {
-		maxStories = 3
-	}
-	if maxStories > len(m.stories) {
-		maxStories = len(m.stories)
-	}
-
-	for i := 0; i < maxStories; i++ {
+	for _, i := range page.Items {
 		story := m.stories[i]
 		t := story.Title
 		if len(t) > maxTitle {
@@ -129,14 +209,14 @@ func (m Model) View(width, height int) string {
 		}
 
 		cursor := "  "
-		if i == m.selected {
+		if i == page.Selected {
 			cursor = style.AccentStyle.Render("▸ ")
 		}
 
 		lines = append(lines, fmt.Sprintf("%s%s %s",
 			cursor,
 			style.AccentStyle.Render(fmt.Sprintf("%d.", i+1)),
-			t,
+			filter.Highlight(t, lipgloss.NewStyle()),
 		))
 
 		// Detail line: domain/snippet + metadata
@@ -153,25 +233,28 @@ func (m Model) View(width, height int) string {
 			detail = snippet
 		}
 
-		meta := fmt.Sprintf("%d pts · %s · %d comments · %s",
-			story.Score, story.By, story.Comments, timeAgo(story.Time))
+		meta := style.SubtitleStyle.Render(fmt.Sprintf("%d pts · ", story.Score)) +
+			filter.Highlight(story.By, style.SubtitleStyle) +
+			style.SubtitleStyle.Render(fmt.Sprintf(" · %d comments · %s", story.Comments, timeAgo(story.Time)))
 
 		if detail != "" {
-			lines = append(lines, fmt.Sprintf("     %s  %s",
-				style.SubtitleStyle.Render(detail),
-				style.SubtitleStyle.Render("· "+meta),
+			lines = append(lines, fmt.Sprintf("     %s  %s%s",
+				filter.Highlight(detail, style.SubtitleStyle),
+				style.SubtitleStyle.Render("· "),
+				meta,
 			))
 		} else {
-			lines = append(lines, fmt.Sprintf("     %s",
-				style.SubtitleStyle.Render(meta),
-			))
+			lines = append(lines, "     "+meta)
 		}
 	}
 
-	lines = append(lines, "")
-	lines = append(lines, style.SubtitleStyle.Render(
-		fmt.Sprintf("  ↑↓ navigate · o open · Updated %s", m.lastUpdated.Format("15:04")),
-	))
+	if len(page.Items) == 0 && filter.Active() {
+		lines = append(lines, style.SubtitleStyle.Render("  No matches"))
+	}
+
+	lines = append(lines, page.MoreBelow())
+	hint := style.SubtitleStyle.Render(panel.Hint())
+	lines = append(lines, hint+m.health.Footer(width-lipgloss.Width(hint)))
 
 	return strings.Join(lines, "\n")
 }
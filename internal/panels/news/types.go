@@ -1,7 +1,5 @@
 package news
 
-import "time"
-
 type Story struct {
 	ID       int
 	Title    string
@@ -30,4 +28,4 @@ type ResponseMsg struct {
 	Error   error
 }
 
-type TickMsg time.Time
+func (m ResponseMsg) FetchError() error { return m.Error }
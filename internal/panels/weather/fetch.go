@@ -3,27 +3,28 @@ package weather
 import (
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pulse/internal/config"
+	"pulse/internal/retry"
 )
 
 func FetchCmd(cfg config.Config) tea.Cmd {
 	return func() tea.Msg {
-		url := fmt.Sprintf(
-			"https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric",
-			cfg.WeatherCity, cfg.WeatherAPIKey,
+		reqURL := fmt.Sprintf(
+			"%s/data/2.5/weather?q=%s&appid=%s&units=metric",
+			cfg.BaseURLs.OpenWeather, url.QueryEscape(cfg.WeatherCity), cfg.WeatherAPIKey,
 		)
 
-		resp, err := http.Get(url)
+		resp, err := cfg.Client().Get(reqURL)
 		if err != nil {
 			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != 200 {
-			return ResponseMsg{Error: fmt.Errorf("API returned %d", resp.StatusCode)}
+		if err := retry.CheckResponse(resp); err != nil {
+			return ResponseMsg{Error: err}
 		}
 
 		var data apiResponse
@@ -50,10 +51,13 @@ func FetchCmd(cfg config.Config) tea.Cmd {
 				Humidity:    data.Main.Humidity,
 				Pressure:    data.Main.Pressure,
 				WindSpeed:   data.Wind.Speed,
+				WindDeg:     data.Wind.Deg,
+				WindGust:    data.Wind.Gust,
 				Clouds:      data.Clouds.All,
 				Visibility:  float64(data.Visibility) / 1000.0,
 				Sunrise:     data.Sys.Sunrise,
 				Sunset:      data.Sys.Sunset,
+				Observed:    data.Dt,
 			},
 		}
 	}
@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pulse/internal/config"
+	"pulse/internal/retry"
+)
+
+const current = `{
+  "name": "São Paulo",
+  "main": {"temp": 21.5, "feels_like": 20.9, "temp_min": 19, "temp_max": 24, "humidity": 64, "pressure": 1016},
+  "weather": [{"main": "Clouds", "description": "broken clouds"}],
+  "wind": {"speed": 3.6, "deg": 140},
+  "clouds": {"all": 75},
+  "visibility": 10000,
+  "dt": 1749000000,
+  "sys": {"sunrise": 1748940000, "sunset": 1748980000}
+}`
+
+func TestFetchCmd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/data/2.5/weather" || q.Get("q") != "São Paulo" || q.Get("appid") != "secret" {
+			http.Error(w, "bad request", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(current))
+	}))
+	defer srv.Close()
+	cfg := config.Config{
+		HTTPClient:    srv.Client(),
+		BaseURLs:      config.BaseURLs{OpenWeather: srv.URL},
+		WeatherCity:   "São Paulo",
+		WeatherAPIKey: "secret",
+	}
+
+	msg := FetchCmd(cfg)().(ResponseMsg)
+	if msg.Error != nil {
+		t.Fatalf("FetchCmd() error: %v", msg.Error)
+	}
+	want := Data{
+		City: "São Paulo", Temp: 21.5, FeelsLike: 20.9, TempMin: 19, TempMax: 24,
+		Condition: "Clouds", Description: "broken clouds", Humidity: 64, Pressure: 1016,
+		WindSpeed: 3.6, WindDeg: 140, Clouds: 75, Visibility: 10,
+		Sunrise: 1748940000, Sunset: 1748980000, Observed: 1749000000,
+	}
+	if msg.Data != want {
+		t.Errorf("Data = %+v, want %+v", msg.Data, want)
+	}
+
+	cfg.WeatherAPIKey = "wrong"
+	msg = FetchCmd(cfg)().(ResponseMsg)
+	var se *retry.StatusError
+	if !errors.As(msg.Error, &se) || se.Code != http.StatusUnauthorized {
+		t.Errorf("FetchCmd() with a bad key = %v, want a 401", msg.Error)
+	}
+}
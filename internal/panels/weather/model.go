@@ -1,62 +1,96 @@
 package weather
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"pulse/internal/config"
+	"pulse/internal/panel"
 	"pulse/internal/style"
 )
 
 type Model struct {
-	config      config.Config
-	data        Data
-	lastUpdated time.Time
-	loading     bool
-	err         error
-	spinner     spinner.Model
-}
-
-func New(cfg config.Config) Model {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = style.AccentStyle
+	config  config.Config
+	title   string
+	data    Data
+	health  panel.Health
+	spinner spinner.Model
+}
+
+type options struct {
+	City   string `yaml:"city"`
+	APIKey string `yaml:"api_key"`
+}
+
+// Definition registers the weather panel type.
+var Definition = panel.Definition{
+	Name:   "weather",
+	Toggle: "w",
+	New: func(cfg config.Config, pc config.PanelConfig) (panel.Panel, error) {
+		return New(cfg, pc)
+	},
+}
+
+func New(cfg config.Config, pc config.PanelConfig) (Model, error) {
+	opts := options{City: cfg.WeatherCity, APIKey: cfg.WeatherAPIKey}
+	if err := pc.Decode(&opts); err != nil {
+		return Model{}, err
+	}
+	cfg.WeatherCity = opts.City
+	cfg.WeatherAPIKey = opts.APIKey
+
+	s := panel.NewSpinner()
 	return Model{
-		config:  cfg,
-		loading: true,
+		config: cfg,
+		title:  panel.Or(pc.Title, "Weather"),
+		health: panel.Health{
+			Interval: panel.Or(pc.Refresh, 10*time.Minute),
+			Loading:  true,
+		},
 		spinner: s,
-	}
+	}, nil
 }
 
-func tickCmd() tea.Cmd {
-	return tea.Tick(10*time.Minute, func(t time.Time) tea.Msg {
-		return TickMsg(t)
-	})
+func (m Model) Init() tea.Cmd {
+	return m.spinner.Tick
 }
 
-func (m Model) Init() tea.Cmd {
-	return tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+func (m Model) Interval() time.Duration { return m.health.Interval }
+
+func (m Model) Health() panel.Health { return m.health }
+
+func (m Model) Fetch() (panel.Panel, tea.Cmd) {
+	m.health.Start()
+	return m, FetchCmd(m.config)
 }
 
-func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+func (m Model) Snapshot() any { return m.data }
+
+func (m Model) Restore(data []byte, saved time.Time) (panel.Panel, error) {
+	if err := json.Unmarshal(data, &m.data); err != nil {
+		return m, err
+	}
+	m.health.Restore(saved)
+	return m, nil
+}
+
+func (m Model) Title() string { return m.title }
+
+func (m Model) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case ResponseMsg:
-		m.loading = false
 		if msg.Error != nil {
-			m.err = msg.Error
+			m.health.Fail(msg.Error)
 		} else {
 			m.data = msg.Data
-			m.err = nil
-			m.lastUpdated = time.Now()
+			m.health.Succeed()
 		}
-		return m, tickCmd()
-
-	case TickMsg:
-		m.loading = true
-		return m, tea.Batch(FetchCmd(m.config), m.spinner.Tick)
+		return m, nil
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -67,32 +101,61 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) HandleKey(msg tea.KeyMsg) (panel.Panel, tea.Cmd) {
+	return m, nil
+}
+
+func (m Model) Actions() []panel.Action {
+	return []panel.Action{{Name: "Change city", Prompt: "City", Refresh: true}}
+}
+
+func (m Model) RunAction(name, arg string) (panel.Panel, error) {
+	switch name {
+	case "Change city":
+		m.config.WeatherCity = strings.TrimSpace(arg)
+	}
+	return m, nil
+}
+
+func (m Model) SelectedURL() string {
+	return ""
+}
+
+func (m Model) Summary() []panel.Line {
+	d := m.data
+	return []panel.Line{
+		{Text: fmt.Sprintf("%s: %.0f°C, %s (feels like %.0f°C)", d.City, d.Temp, d.Description, d.FeelsLike)},
+		{Text: fmt.Sprintf("H %.0f° L %.0f° · humidity %d%% · wind %.1fm/s", d.TempMax, d.TempMin, d.Humidity, d.WindSpeed)},
+		{Text: fmt.Sprintf("Sunrise %s · sunset %s",
+			time.Unix(d.Sunrise, 0).Format("15:04"), time.Unix(d.Sunset, 0).Format("15:04"))},
+	}
+}
+
 func (m Model) View(width, height int) string {
-	if m.loading && m.lastUpdated.IsZero() {
-		title := style.TitleStyle.Render("🌤 Weather")
-		return fmt.Sprintf("%s\n\n  %s Loading weather...", title, m.spinner.View())
+	if m.health.Loading && m.health.Updated.IsZero() {
+		title := style.TitleStyle.Render("🌤 " + m.title)
+		return fmt.Sprintf("%s\n\n  %s Loading weather...", title, panel.SpinnerView(m.spinner))
 	}
 
-	if m.err != nil && m.lastUpdated.IsZero() {
-		title := style.TitleStyle.Render("🌤 Weather")
-		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.err.Error()))
+	if m.health.Err != nil && m.health.Updated.IsZero() {
+		title := style.TitleStyle.Render("🌤 " + m.title)
+		return fmt.Sprintf("%s\n\n%s", title, style.ErrorStyle.Render("  "+m.health.Err.Error()))
 	}
 
 	d := m.data
 	icon := weatherIcon(d.Condition)
-	title := style.TitleStyle.Render(fmt.Sprintf("%s Weather", icon))
+	title := style.TitleStyle.Render(fmt.Sprintf("%s %s", icon, m.title))
 
 	sunrise := time.Unix(d.Sunrise, 0).Format("15:04")
 	sunset := time.Unix(d.Sunset, 0).Format("15:04")
-	updated := m.lastUpdated.Format("15:04")
 
 	var lines []string
 	lines = append(lines, title)
 	lines = append(lines, "")
 	lines = append(lines, fmt.Sprintf("  %s %s",
-		style.BoldWhite.Render(d.City), loadingDot(m.loading)))
+		style.BoldText.Render(d.City), loadingDot(m.health.Loading)))
 	lines = append(lines, fmt.Sprintf("  %s  %s",
-		style.BoldWhite.Render(fmt.Sprintf("%.0f°C", d.Temp)),
+		style.BoldText.Render(fmt.Sprintf("%.0f°C", d.Temp)),
 		style.SubtitleStyle.Render(d.Description)))
 	lines = append(lines, fmt.Sprintf("  %s",
 		style.SubtitleStyle.Render(fmt.Sprintf("Feels like %.0f°C", d.FeelsLike))))
@@ -109,12 +172,52 @@ func (m Model) View(width, height int) string {
 	lines = append(lines, fmt.Sprintf("  %s  %s",
 		style.SubtitleStyle.Render(fmt.Sprintf("🌅 %s", sunrise)),
 		style.SubtitleStyle.Render(fmt.Sprintf("🌇 %s", sunset))))
+
+	// Extra details when there is room, e.g. while zoomed
+	if height >= 16 {
+		lines = append(lines, "")
+		lines = append(lines, detailLines(d)...)
+	}
+
 	lines = append(lines, "")
-	lines = append(lines, style.SubtitleStyle.Render(fmt.Sprintf("  Updated %s", updated)))
+	lines = append(lines, "  "+m.health.Footer(width-2))
 
 	return strings.Join(lines, "\n")
 }
 
+func detailLines(d Data) []string {
+	wind := fmt.Sprintf("Wind %s %.1fm/s", compass(d.WindDeg), d.WindSpeed)
+	if d.WindGust > 0 {
+		wind += fmt.Sprintf(", gusts %.1fm/s", d.WindGust)
+	}
+	daylight := time.Duration(d.Sunset-d.Sunrise) * time.Second
+	lines := []string{
+		"  " + style.SubtitleStyle.Render(wind),
+		"  " + style.SubtitleStyle.Render(fmt.Sprintf("Dew point %.0f°C · Daylight %dh%02dm",
+			dewPoint(d.Temp, d.Humidity), int(daylight.Hours()), int(daylight.Minutes())%60)),
+	}
+	if d.Observed > 0 {
+		lines = append(lines, "  "+style.SubtitleStyle.Render(
+			"Observed "+time.Unix(d.Observed, 0).Format("15:04")))
+	}
+	return lines
+}
+
+func compass(deg int) string {
+	dirs := []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+	return dirs[((deg%360+360)%360+22)/45%8]
+}
+
+// dewPoint approximates the dew point with the Magnus formula.
+func dewPoint(temp float64, humidity int) float64 {
+	if humidity <= 0 {
+		return math.NaN()
+	}
+	const a, b = 17.62, 243.12
+	g := math.Log(float64(humidity)/100) + a*temp/(b+temp)
+	return b * g / (a - g)
+}
+
 func loadingDot(loading bool) string {
 	if loading {
 		return style.AccentStyle.Render("*")
@@ -1,7 +1,5 @@
 package weather
 
-import "time"
-
 type apiResponse struct {
 	Name string `json:"name"`
 	Main struct {
@@ -18,11 +16,14 @@ type apiResponse struct {
 	} `json:"weather"`
 	Wind struct {
 		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+		Gust  float64 `json:"gust"`
 	} `json:"wind"`
 	Clouds struct {
 		All int `json:"all"`
 	} `json:"clouds"`
-	Visibility int `json:"visibility"`
+	Visibility int   `json:"visibility"`
+	Dt         int64 `json:"dt"`
 	Sys        struct {
 		Sunrise int64 `json:"sunrise"`
 		Sunset  int64 `json:"sunset"`
@@ -40,10 +41,13 @@ type Data struct {
 	Humidity    int
 	Pressure    int
 	WindSpeed   float64
+	WindDeg     int
+	WindGust    float64
 	Clouds      int
 	Visibility  float64 // km
 	Sunrise     int64
 	Sunset      int64
+	Observed    int64
 }
 
 type ResponseMsg struct {
@@ -51,4 +55,4 @@ type ResponseMsg struct {
 	Error error
 }
 
-type TickMsg time.Time
+func (m ResponseMsg) FetchError() error { return m.Error }
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// StatusError is returned for a non-200 response. RetryAt is set when the
+// server said when to come back, via Retry-After or GitHub's
+// X-RateLimit-Reset.
+type StatusError struct {
+	Code    int
+	RetryAt time.Time
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned %d", e.Code)
+}
+
+// RateLimited reports whether the server refused the request because of a
+// rate limit.
+func (e *StatusError) RateLimited() bool {
+	return e.Code == http.StatusTooManyRequests || !e.RetryAt.IsZero()
+}
+
+// CheckResponse returns a *StatusError for any status other than 200.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	err := &StatusError{Code: resp.StatusCode}
+	if at, ok := retryAfter(resp.Header); ok {
+		err.RetryAt = at
+	} else if at, ok := RateLimitReset(resp.Header); ok {
+		err.RetryAt = at
+	}
+	return err
+}
+
+// RateLimitReset returns the reset time when X-RateLimit-Remaining says the
+// quota is used up.
+func RateLimitReset(h http.Header) (time.Time, bool) {
+	if h.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), true
+}
+
+// retryAfter parses Retry-After in either delay-seconds or HTTP-date form.
+func retryAfter(h http.Header) (time.Time, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return time.Time{}, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Now().Add(time.Duration(secs) * time.Second), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// Retryable reports whether err is worth retrying before the next regular
+// refresh: network failures, 5xx responses and rate limits.
+func Retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code >= 500 || se.RateLimited()
+	}
+	var ue *url.Error
+	var ne net.Error
+	return errors.As(err, &ue) || errors.As(err, &ne)
+}
@@ -0,0 +1,60 @@
+// Package retry classifies fetch errors and decides how long to wait before
+// trying again.
+package retry
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy is an exponential backoff: the first retry waits Base, each further
+// consecutive failure doubles the wait up to Max, and every wait is
+// randomised by ±Jitter.
+type Policy struct {
+	Base   time.Duration `yaml:"base"`
+	Max    time.Duration `yaml:"max"`
+	Jitter float64       `yaml:"jitter"`
+}
+
+var Default = Policy{
+	Base:   5 * time.Second,
+	Max:    10 * time.Minute,
+	Jitter: 0.2,
+}
+
+// Merge returns p with zero fields filled in from fallback.
+func (p Policy) Merge(fallback Policy) Policy {
+	if p.Base == 0 {
+		p.Base = fallback.Base
+	}
+	if p.Max == 0 {
+		p.Max = fallback.Max
+	}
+	if p.Jitter == 0 {
+		p.Jitter = fallback.Jitter
+	}
+	return p
+}
+
+// Delay returns how long to wait after the given number of consecutive
+// failures ending in err. ok is false when err isn't retryable and the
+// caller should fall back to its regular interval. A server-supplied retry
+// time always wins over the computed backoff.
+func (p Policy) Delay(err error, failures int) (d time.Duration, ok bool) {
+	var se *StatusError
+	if errors.As(err, &se) && !se.RetryAt.IsZero() {
+		return max(time.Until(se.RetryAt), time.Second), true
+	}
+	if !Retryable(err) {
+		return 0, false
+	}
+
+	d = p.Base
+	for i := 1; i < failures && d < p.Max; i++ {
+		d *= 2
+	}
+	d = min(d, p.Max)
+	f := 1 + p.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(d) * f), true
+}
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDelay(t *testing.T) {
+	p := Policy{Base: time.Second, Max: 10 * time.Second}
+	netErr := &url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("refused")}
+
+	tests := []struct {
+		name     string
+		err      error
+		failures int
+		want     time.Duration
+		ok       bool
+	}{
+		{"first failure waits base", netErr, 1, time.Second, true},
+		{"second failure doubles", netErr, 2, 2 * time.Second, true},
+		{"fourth failure", netErr, 4, 8 * time.Second, true},
+		{"capped at max", netErr, 10, 10 * time.Second, true},
+		{"server error", &StatusError{Code: 503}, 1, time.Second, true},
+		{"rate limited", &StatusError{Code: 429}, 3, 4 * time.Second, true},
+		{"client error", &StatusError{Code: 404}, 1, 0, false},
+		{"decode error", errors.New("decode failed"), 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.Delay(tt.err, tt.failures)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Delay() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestDelayJitter(t *testing.T) {
+	p := Policy{Base: 10 * time.Second, Max: time.Minute, Jitter: 0.2}
+	for range 100 {
+		d, _ := p.Delay(&StatusError{Code: 500}, 1)
+		if d < 8*time.Second || d > 12*time.Second {
+			t.Fatalf("Delay() = %v, want within 20%% of 10s", d)
+		}
+	}
+}
+
+func TestDelayRetryAt(t *testing.T) {
+	p := Policy{Base: time.Second, Max: time.Minute}
+
+	d, ok := p.Delay(&StatusError{Code: 429, RetryAt: time.Now().Add(30 * time.Second)}, 1)
+	if !ok || d < 29*time.Second || d > 30*time.Second {
+		t.Errorf("Delay() = %v, %v; want about 30s", d, ok)
+	}
+	// A reset time in the past still waits a moment.
+	d, ok = p.Delay(&StatusError{Code: 403, RetryAt: time.Now().Add(-time.Minute)}, 1)
+	if !ok || d != time.Second {
+		t.Errorf("Delay() = %v, %v; want 1s", d, ok)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := Policy{Base: time.Second}.Merge(Default)
+	want := Policy{Base: time.Second, Max: Default.Max, Jitter: Default.Jitter}
+	if got != want {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	now := time.Now()
+	date := now.Add(time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		code    int
+		header  http.Header
+		retryAt time.Time // zero when none is expected
+		limited bool
+	}{
+		{"ok", 200, nil, time.Time{}, false},
+		{"server error", 502, nil, time.Time{}, false},
+		{"too many requests", 429, nil, time.Time{}, true},
+		{"retry after seconds", 503, http.Header{"Retry-After": {"120"}}, now.Add(2 * time.Minute), true},
+		{"retry after date", 503, http.Header{"Retry-After": {date.Format(http.TimeFormat)}}, date, true},
+		{"retry after garbage", 503, http.Header{"Retry-After": {"soon"}}, time.Time{}, false},
+		{"quota used up", 403, http.Header{
+			"X-Ratelimit-Remaining": {"0"},
+			"X-Ratelimit-Reset":     {"4102444800"},
+		}, time.Unix(4102444800, 0), true},
+		{"quota left", 403, http.Header{
+			"X-Ratelimit-Remaining": {"12"},
+			"X-Ratelimit-Reset":     {"4102444800"},
+		}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResponse(&http.Response{StatusCode: tt.code, Header: tt.header})
+			if tt.code == 200 {
+				if err != nil {
+					t.Fatalf("CheckResponse() = %v, want nil", err)
+				}
+				return
+			}
+			var se *StatusError
+			if !errors.As(err, &se) {
+				t.Fatalf("CheckResponse() = %v, want *StatusError", err)
+			}
+			if se.Code != tt.code {
+				t.Errorf("Code = %d, want %d", se.Code, tt.code)
+			}
+			if d := se.RetryAt.Sub(tt.retryAt).Abs(); d > time.Second || se.RetryAt.IsZero() != tt.retryAt.IsZero() {
+				t.Errorf("RetryAt = %v, want %v", se.RetryAt, tt.retryAt)
+			}
+			if se.RateLimited() != tt.limited {
+				t.Errorf("RateLimited() = %v, want %v", se.RateLimited(), tt.limited)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&url.Error{Op: "Get", URL: "http://example.com", Err: errors.New("timeout")}, true},
+		{&StatusError{Code: 500}, true},
+		{&StatusError{Code: 429}, true},
+		{&StatusError{Code: 401}, false},
+		{errors.New("decode failed"), false},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.err); got != tt.want {
+			t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
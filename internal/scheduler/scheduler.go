@@ -0,0 +1,178 @@
+// Package scheduler decides when each panel fetches. It keeps exactly one
+// timer per panel, so manual refreshes and show/hide toggles can't start
+// duplicate polling loops, and it never runs two fetches for one panel at
+// the same time.
+package scheduler
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/retry"
+)
+
+// Jitter is the fraction by which intervals are randomly stretched or
+// shortened, so panels sharing an interval don't fire in lockstep.
+const Jitter = 0.1
+
+// TickMsg fires when a panel's timer elapses. Ticks from a timer that was
+// since stopped or superseded are ignored by Tick.
+type TickMsg struct {
+	ID  string
+	gen int
+}
+
+type timer struct {
+	interval time.Duration
+	policy   retry.Policy
+	gen      int
+	active   bool
+	inFlight bool
+	pending  bool // refetch once the fetch in flight is done
+	failures int
+	hold     time.Time // server asked us not to call before this
+}
+
+type Scheduler struct {
+	timers map[string]*timer
+}
+
+func New() *Scheduler {
+	return &Scheduler{timers: map[string]*timer{}}
+}
+
+// Add registers a panel with its refresh interval and the backoff used
+// after failures. Timers start inactive.
+func (s *Scheduler) Add(id string, interval time.Duration, policy retry.Policy) {
+	s.timers[id] = &timer{interval: interval, policy: policy}
+}
+
+// Start activates a panel's timer, e.g. when it becomes visible. It reports
+// whether the caller should fetch now; if a fetch is already running, the
+// next timer is armed when it completes. While a server rate limit is in
+// force it instead returns a command that fires when the limit lifts.
+func (s *Scheduler) Start(id string) (bool, tea.Cmd) {
+	t := s.timers[id]
+	if t == nil || t.active {
+		return false, nil
+	}
+	t.active = true
+	t.gen++
+	if wait := time.Until(t.hold); wait > 0 && !t.inFlight {
+		return false, after(id, t.gen, wait)
+	}
+	return s.begin(t), nil
+}
+
+// Stop deactivates a panel's timer. A pending tick is discarded and no new
+// one is armed until Start is called again.
+func (s *Scheduler) Stop(id string) {
+	if t := s.timers[id]; t != nil {
+		t.active = false
+		t.gen++
+	}
+}
+
+// Trigger requests an immediate fetch. It is coalesced with a fetch that is
+// already in flight, and refused while the server's rate limit is in force;
+// in both cases it reports false.
+func (s *Scheduler) Trigger(id string) bool {
+	t := s.timers[id]
+	if t == nil || !t.active || time.Now().Before(t.hold) {
+		return false
+	}
+	t.gen++
+	return s.begin(t)
+}
+
+// Refetch requests a fetch that must not be coalesced, e.g. because the
+// panel's settings changed since the fetch in flight started. It reports
+// whether the caller should fetch now; otherwise the next fetch starts as
+// soon as the one in flight is Done.
+func (s *Scheduler) Refetch(id string) bool {
+	t := s.timers[id]
+	if t == nil {
+		return false
+	}
+	if t.inFlight {
+		t.pending = true
+		return false
+	}
+	return s.Trigger(id)
+}
+
+// Tick handles a timer firing and reports whether the caller should fetch.
+func (s *Scheduler) Tick(msg TickMsg) bool {
+	t := s.timers[msg.ID]
+	if t == nil || !t.active || msg.gen != t.gen {
+		return false
+	}
+	return s.begin(t)
+}
+
+// Done records that a panel's fetch finished and arms its next timer. After
+// a success the regular interval applies; after a retryable failure the
+// panel's backoff policy does, and after a Refetch none. notBefore, when
+// set, delays the next fetch until the server's quota resets.
+func (s *Scheduler) Done(id string, err error, notBefore time.Time) tea.Cmd {
+	t := s.timers[id]
+	if t == nil {
+		return nil
+	}
+	t.inFlight = false
+
+	d := jitter(t.interval)
+	if err == nil {
+		t.failures = 0
+	} else {
+		t.failures++
+		if wait, ok := t.policy.Delay(err, t.failures); ok {
+			d = wait
+		}
+	}
+	if t.pending {
+		t.pending = false
+		d = 0
+	}
+	var se *retry.StatusError
+	if errors.As(err, &se) && se.RetryAt.After(notBefore) {
+		notBefore = se.RetryAt
+	}
+	t.hold = notBefore
+	if wait := time.Until(notBefore); wait > d {
+		d = wait
+	}
+
+	if !t.active {
+		return nil
+	}
+	t.gen++
+	return after(id, t.gen, d)
+}
+
+// InFlight reports whether a fetch is running for the panel.
+func (s *Scheduler) InFlight(id string) bool {
+	t := s.timers[id]
+	return t != nil && t.inFlight
+}
+
+func (s *Scheduler) begin(t *timer) bool {
+	if t.inFlight {
+		return false
+	}
+	t.inFlight = true
+	return true
+}
+
+func after(id string, gen int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return TickMsg{ID: id, gen: gen}
+	})
+}
+
+func jitter(d time.Duration) time.Duration {
+	f := 1 + Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(d) * f)
+}
@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"pulse/internal/retry"
+)
+
+func newScheduler() *Scheduler {
+	s := New()
+	s.Add("a", time.Minute, retry.Default)
+	return s
+}
+
+func TestSingleFlight(t *testing.T) {
+	s := newScheduler()
+
+	if ok, _ := s.Start("a"); !ok {
+		t.Fatal("Start() = false, want a fetch")
+	}
+	if s.Trigger("a") {
+		t.Error("Trigger() during a fetch = true, want it coalesced")
+	}
+	if s.Tick(TickMsg{ID: "a", gen: s.timers["a"].gen}) {
+		t.Error("Tick() during a fetch = true, want it coalesced")
+	}
+	if !s.InFlight("a") {
+		t.Error("InFlight() = false during a fetch")
+	}
+
+	if cmd := s.Done("a", nil, time.Time{}); cmd == nil {
+		t.Error("Done() = nil, want the next tick armed")
+	}
+	if s.InFlight("a") {
+		t.Error("InFlight() = true after Done")
+	}
+	if !s.Trigger("a") {
+		t.Error("Trigger() after Done = false, want a fetch")
+	}
+}
+
+func TestStaleTick(t *testing.T) {
+	s := newScheduler()
+	s.Start("a")
+	s.Done("a", nil, time.Time{})
+	stale := TickMsg{ID: "a", gen: s.timers["a"].gen}
+
+	// A manual refresh supersedes the pending tick.
+	s.Trigger("a")
+	s.Done("a", nil, time.Time{})
+	if s.Tick(stale) {
+		t.Error("Tick() with a superseded timer = true")
+	}
+	if !s.Tick(TickMsg{ID: "a", gen: s.timers["a"].gen}) {
+		t.Error("Tick() with the current timer = false")
+	}
+}
+
+func TestStopStart(t *testing.T) {
+	s := newScheduler()
+	s.Start("a")
+	s.Stop("a")
+	if cmd := s.Done("a", nil, time.Time{}); cmd != nil {
+		t.Error("Done() on a stopped panel armed a tick")
+	}
+	if s.Trigger("a") {
+		t.Error("Trigger() on a stopped panel = true")
+	}
+
+	// Showing the panel again mid-fetch must not start a second fetch.
+	s.Start("a")
+	s.Stop("a")
+	if ok, _ := s.Start("a"); ok {
+		t.Error("Start() while a fetch is in flight = true")
+	}
+	if ok, _ := s.Start("a"); ok {
+		t.Error("second Start() = true, want the timer already active")
+	}
+}
+
+func TestRefetch(t *testing.T) {
+	s := newScheduler()
+	s.Start("a")
+	if s.Refetch("a") {
+		t.Fatal("Refetch() during a fetch = true, want it queued")
+	}
+
+	cmd := s.Done("a", nil, time.Time{})
+	if cmd == nil {
+		t.Fatal("Done() = nil, want the queued fetch armed")
+	}
+	start := time.Now()
+	msg := cmd().(TickMsg)
+	if time.Since(start) > time.Second {
+		t.Errorf("queued fetch waited %s, want it straight away", time.Since(start))
+	}
+	if !s.Tick(msg) {
+		t.Error("Tick() for the queued fetch = false")
+	}
+
+	s.Done("a", nil, time.Time{})
+	if !s.Refetch("a") {
+		t.Error("Refetch() with nothing in flight = false, want a fetch")
+	}
+}
+
+func TestHold(t *testing.T) {
+	s := newScheduler()
+	s.Start("a")
+	s.Done("a", &retry.StatusError{Code: 429, RetryAt: time.Now().Add(time.Hour)}, time.Time{})
+	if s.Trigger("a") {
+		t.Error("Trigger() while rate limited = true")
+	}
+
+	s.Stop("a")
+	ok, cmd := s.Start("a")
+	if ok || cmd == nil {
+		t.Errorf("Start() while rate limited = %v, %v; want a delayed tick", ok, cmd != nil)
+	}
+}
+
+func TestUnknownPanel(t *testing.T) {
+	s := New()
+	if ok, cmd := s.Start("x"); ok || cmd != nil {
+		t.Error("Start() on an unknown panel did something")
+	}
+	if s.Trigger("x") || s.Tick(TickMsg{ID: "x"}) || s.InFlight("x") {
+		t.Error("unknown panel reported a fetch")
+	}
+	if s.Done("x", nil, time.Time{}) != nil {
+		t.Error("Done() on an unknown panel armed a tick")
+	}
+}
@@ -0,0 +1,151 @@
+// Package snapshot fetches every selected panel once and prints the result,
+// for running pulse from cron jobs, shell prompts and CI without a TUI.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"pulse/internal/panel"
+)
+
+// Formats lists the accepted values for Run's format argument.
+var Formats = []string{"json", "text", "markdown"}
+
+type result struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Data  any    `json:"data,omitempty"`
+	Error string `json:"error,omitempty"`
+
+	lines []panel.Line
+}
+
+type report struct {
+	Generated time.Time `json:"generated"`
+	Panels    []result  `json:"panels"`
+}
+
+// Run fetches each instance once, concurrently, and writes the combined
+// snapshot to w. It returns an error if any fetch failed, after writing
+// whatever data the other panels returned.
+func Run(w io.Writer, instances []panel.Instance, format string) error {
+	results := make([]result, len(instances))
+	var wg sync.WaitGroup
+	for i, in := range instances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = fetch(in)
+		}()
+	}
+	wg.Wait()
+
+	var err error
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report{Generated: time.Now(), Panels: results})
+	case "text":
+		err = writeText(w, results)
+	case "markdown":
+		err = writeMarkdown(w, results)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, r := range results {
+		if r.Error != "" {
+			failed = append(failed, r.ID)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d panels failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func fetch(in panel.Instance) result {
+	r := result{ID: in.ID, Type: in.Def.Name, Title: in.Panel.Title()}
+
+	p, cmd := in.Panel.Fetch()
+	if cmd != nil {
+		msg := cmd()
+		if res, ok := msg.(panel.Result); ok && res.FetchError() != nil {
+			r.Error = res.FetchError().Error()
+			return r
+		}
+		p, _ = p.Update(msg)
+	}
+
+	if c, ok := p.(panel.Cacheable); ok {
+		r.Data = c.Snapshot()
+	}
+	if s, ok := p.(panel.Summarizer); ok {
+		r.lines = s.Summary()
+	}
+	return r
+}
+
+func writeText(w io.Writer, results []result) error {
+	var b strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n", r.Title)
+		if r.Error != "" {
+			fmt.Fprintf(&b, "  error: %s\n", r.Error)
+			continue
+		}
+		for _, l := range r.lines {
+			fmt.Fprintf(&b, "  %s\n", l.Text)
+			if l.URL != "" {
+				fmt.Fprintf(&b, "    %s\n", l.URL)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdown(w io.Writer, results []result) error {
+	var b strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", r.Title)
+		if r.Error != "" {
+			fmt.Fprintf(&b, "> **Error:** %s\n", r.Error)
+			continue
+		}
+		for _, l := range r.lines {
+			if l.URL != "" {
+				fmt.Fprintf(&b, "- [%s](%s)\n", escapeMarkdown(l.Text), l.URL)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", escapeMarkdown(l.Text))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`, "`", "\\`",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
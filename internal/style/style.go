@@ -1,58 +1,84 @@
+// Package style holds the Lip Gloss styles every panel renders with. They
+// start out in the default dark theme; Apply rebuilds them from another.
 package style
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 var (
-	Purple = lipgloss.Color("#A855F7")
-	Cyan   = lipgloss.Color("#22D3EE")
-	Green  = lipgloss.Color("#22C55E")
-	Red    = lipgloss.Color("#EF4444")
-	Yellow = lipgloss.Color("#EAB308")
-	Dim    = lipgloss.Color("#666666")
-	White  = lipgloss.Color("#FFFFFF")
+	TitleStyle       lipgloss.Style
+	SubtitleStyle    lipgloss.Style
+	BoldText         lipgloss.Style
+	AccentStyle      lipgloss.Style
+	ErrorStyle       lipgloss.Style
+	PositiveStyle    lipgloss.Style
+	NegativeStyle    lipgloss.Style
+	WarningStyle     lipgloss.Style
+	PanelStyle       lipgloss.Style
+	PanelActiveStyle lipgloss.Style
+	HeaderStyle      lipgloss.Style
+	StatusBarStyle   lipgloss.Style
+)
+
+func init() {
+	Apply(Themes["dark"])
+}
 
-	BorderNormal = lipgloss.Color("#333333")
-	BorderActive = Purple
+// Apply rebuilds the styles from t. Styles are read when rendering, so it
+// can be called while the dashboard runs. Colours are downsampled to the
+// terminal's profile; with NO_COLOR (or a terminal without colour) the
+// focused panel gets a thick border since its colour can't tell it apart.
+func Apply(t Theme) {
+	c := func(hex string) lipgloss.Color { return lipgloss.Color(hex) }
 
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Purple)
+		Bold(true).
+		Foreground(c(t.Primary))
 
 	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(Dim)
+		Foreground(c(t.Subtle))
 
-	BoldWhite = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(White)
+	BoldText = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(c(t.Text))
 
 	AccentStyle = lipgloss.NewStyle().
-			Foreground(Cyan)
+		Foreground(c(t.Accent))
 
 	ErrorStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(Red)
+		Bold(true).
+		Foreground(c(t.Negative))
 
 	PositiveStyle = lipgloss.NewStyle().
-			Foreground(Green)
+		Foreground(c(t.Positive))
 
 	NegativeStyle = lipgloss.NewStyle().
-			Foreground(Red)
+		Foreground(c(t.Negative))
+
+	WarningStyle = lipgloss.NewStyle().
+		Foreground(c(t.Warning))
 
 	PanelStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(BorderNormal).
-			Padding(0, 1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(c(t.Border)).
+		Padding(0, 1)
 
+	activeBorder := lipgloss.RoundedBorder()
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		activeBorder = lipgloss.ThickBorder()
+	}
 	PanelActiveStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(BorderActive).
-				Padding(0, 1)
+		Border(activeBorder).
+		BorderForeground(c(t.Primary)).
+		Padding(0, 1)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Padding(0, 1)
+		Bold(true).
+		Padding(0, 1)
 
 	StatusBarStyle = lipgloss.NewStyle().
-			Foreground(Dim).
-			Padding(0, 1)
-)
+		Foreground(c(t.Subtle)).
+		Padding(0, 1)
+}
@@ -0,0 +1,130 @@
+package style
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is a colour palette. Colours are hex values ("#A855F7") or ANSI
+// colour numbers ("5").
+type Theme struct {
+	Base     string `yaml:"base"`     // built-in theme the others override
+	Primary  string `yaml:"primary"`  // titles, focused border
+	Accent   string `yaml:"accent"`   // selection, highlights
+	Text     string `yaml:"text"`     // emphasised text
+	Subtle   string `yaml:"subtle"`   // secondary text, status bar
+	Border   string `yaml:"border"`   // unfocused borders
+	Positive string `yaml:"positive"` // gains, healthy
+	Negative string `yaml:"negative"` // losses, errors
+	Warning  string `yaml:"warning"`  // stale data, rate limits
+}
+
+// UnmarshalYAML accepts a bare name ("theme: light") as well as a mapping.
+func (t *Theme) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&t.Base)
+	}
+	type plain Theme
+	return node.Decode((*plain)(t))
+}
+
+// Themes are the built-in palettes. "auto" picks dark or light from the
+// terminal's background.
+var Themes = map[string]Theme{
+	"dark": {
+		Primary:  "#A855F7",
+		Accent:   "#22D3EE",
+		Text:     "#FFFFFF",
+		Subtle:   "#666666",
+		Border:   "#333333",
+		Positive: "#22C55E",
+		Negative: "#EF4444",
+		Warning:  "#EAB308",
+	},
+	"light": {
+		Primary:  "#7E22CE",
+		Accent:   "#0E7490",
+		Text:     "#111827",
+		Subtle:   "#4B5563",
+		Border:   "#9CA3AF",
+		Positive: "#15803D",
+		Negative: "#B91C1C",
+		Warning:  "#A16207",
+	},
+	"solarized": {
+		Primary:  "#6C71C4",
+		Accent:   "#2AA198",
+		Text:     "#EEE8D5",
+		Subtle:   "#839496",
+		Border:   "#586E75",
+		Positive: "#859900",
+		Negative: "#DC322F",
+		Warning:  "#B58900",
+	},
+	"high-contrast": {
+		Primary:  "#FFFF00",
+		Accent:   "#00FFFF",
+		Text:     "#FFFFFF",
+		Subtle:   "#D0D0D0",
+		Border:   "#FFFFFF",
+		Positive: "#00FF00",
+		Negative: "#FF5555",
+		Warning:  "#FFAF00",
+	},
+}
+
+var colorPattern = regexp.MustCompile(`^(#[0-9A-Fa-f]{3}|#[0-9A-Fa-f]{6}|[0-9]{1,3})$`)
+
+// Resolve fills t's unset colours from its base theme (dark by default) and
+// checks that every colour parses.
+func Resolve(t Theme) (Theme, error) {
+	name := t.Base
+	if name == "" {
+		name = "dark"
+	}
+	if name == "auto" {
+		name = "light"
+		if lipgloss.HasDarkBackground() {
+			name = "dark"
+		}
+	}
+	base, ok := Themes[name]
+	if !ok {
+		names := []string{"auto"}
+		for n := range Themes {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return t, fmt.Errorf("theme: unknown base %q (want one of %s)", t.Base, strings.Join(names, ", "))
+	}
+
+	out := base
+	out.Base = name
+	for _, f := range []struct {
+		name     string
+		dst, src *string
+	}{
+		{"primary", &out.Primary, &t.Primary},
+		{"accent", &out.Accent, &t.Accent},
+		{"text", &out.Text, &t.Text},
+		{"subtle", &out.Subtle, &t.Subtle},
+		{"border", &out.Border, &t.Border},
+		{"positive", &out.Positive, &t.Positive},
+		{"negative", &out.Negative, &t.Negative},
+		{"warning", &out.Warning, &t.Warning},
+	} {
+		if *f.src == "" {
+			continue
+		}
+		if !colorPattern.MatchString(*f.src) {
+			return t, fmt.Errorf("theme: %s: %q is not a hex colour or ANSI colour number", f.name, *f.src)
+		}
+		*f.dst = *f.src
+	}
+	return out, nil
+}
@@ -0,0 +1,30 @@
+// Package terminal is the dashboard's standard output. The program renders
+// frames through it, and sequences written outside the renderer, such as
+// alert notifications, go through it too so they land between frames
+// instead of in the middle of one.
+package terminal
+
+import (
+	"os"
+	"sync"
+)
+
+// File serializes writes to f. It embeds f so the program still sees a
+// terminal it can size and set up.
+type File struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.File.Write(p)
+}
+
+func (f *File) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// Stdout is standard output with writes serialized.
+var Stdout = &File{File: os.Stdout}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// fuzzyScore matches pattern against s as a case-insensitive subsequence.
+// Matches at word starts and runs of consecutive characters score higher,
+// gaps lower, so "rw" prefers "Refresh weather" over "Remove coin".
+func fuzzyScore(pattern, s string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+	score, run := 0, 0
+	prev := ' '
+	p := []rune(pattern)
+	pi := 0
+	for _, r := range s {
+		if pi < len(p) && unicode.ToLower(r) == unicode.ToLower(p[pi]) {
+			run++
+			score += run
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3
+			}
+			pi++
+		} else {
+			if pi > 0 && pi < len(p) {
+				score--
+			}
+			run = 0
+		}
+		prev = r
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score - utf8.RuneCountInString(s)/10, true
+}
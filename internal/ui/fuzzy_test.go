@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		titles  []string // best match first
+		misses  []string
+	}{
+		{"rw", []string{"Refresh weather", "Show crypto wallet"}, []string{"Remove coin"}},
+		{"zoom", []string{"Zoom panel", "Zone of my own"}, []string{"Zoo"}},
+		{"open", []string{"Open URL", "Open URL in the browser", "Copy selected item's pen name"}, nil},
+		{"REF", []string{"Refresh all"}, []string{"Theme: dark"}},
+		{"", []string{"Anything"}, nil},
+	}
+	for _, tt := range tests {
+		var matched []string
+		scores := map[string]int{}
+		for _, s := range append(slices.Clone(tt.titles), tt.misses...) {
+			if score, ok := fuzzyScore(tt.pattern, s); ok {
+				matched = append(matched, s)
+				scores[s] = score
+			}
+		}
+		slices.SortStableFunc(matched, func(a, b string) int { return scores[b] - scores[a] })
+		if !slices.Equal(matched, tt.titles) {
+			t.Errorf("%q ranks %q, want %q", tt.pattern, matched, tt.titles)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"pulse/internal/panel"
+	"pulse/internal/style"
+)
+
+// newHelp returns a help bubble styled with the current theme.
+func newHelp() help.Model {
+	h := help.New()
+	h.ShortSeparator = "  ·  "
+	h.FullSeparator = "    "
+	h.Styles.ShortKey = style.SubtitleStyle.Bold(true)
+	h.Styles.ShortDesc = style.SubtitleStyle
+	h.Styles.ShortSeparator = style.SubtitleStyle
+	h.Styles.Ellipsis = style.SubtitleStyle
+	h.Styles.FullKey = style.AccentStyle
+	h.Styles.FullDesc = style.SubtitleStyle
+	h.Styles.FullSeparator = style.SubtitleStyle
+	return h
+}
+
+// shortHelp is the status bar's one-line help.
+func (m Model) shortHelp() []key.Binding {
+	zoom := m.keys.Zoom
+	if m.zoomed {
+		zoom.SetHelp(zoom.Help().Key, "unzoom")
+	}
+	return []key.Binding{m.keys.Quit, m.keys.Refresh, m.keys.Tab, zoom, m.keys.Palette, m.keys.Help}
+}
+
+// globalHelp lists every dashboard-wide binding.
+func (m Model) globalHelp() []key.Binding {
+	k := m.keys
+	return []key.Binding{k.Quit, k.Refresh, k.Tab, k.Focus, k.Zoom, k.Palette, k.Help}
+}
+
+// toggleHelp lists one binding per toggle key; instances sharing a key are
+// described together.
+func (m Model) toggleHelp() []key.Binding {
+	var keys []string
+	ids := map[string][]string{}
+	for _, p := range m.panels {
+		k := p.toggle.Help().Key
+		if !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+		ids[k] = append(ids[k], p.id)
+	}
+	var bindings []key.Binding
+	for _, k := range keys {
+		bindings = append(bindings, key.NewBinding(
+			key.WithKeys(k), key.WithHelp(k, "toggle "+strings.Join(ids[k], ", ")),
+		))
+	}
+	return bindings
+}
+
+// helpView renders the ? overlay: global bindings, panel toggles and the
+// keys the focused panel handles, centred in the grid area.
+func (m Model) helpView() string {
+	type group struct {
+		title    string
+		bindings []key.Binding
+	}
+	groups := []group{
+		{"Global", m.globalHelp()},
+		{"Panels", m.toggleHelp()},
+	}
+	if m.focused < len(m.panels) && m.panels[m.focused].visible {
+		p := m.panels[m.focused].panel
+		var bindings []key.Binding
+		if kh, ok := p.(panel.KeyHelper); ok {
+			bindings = kh.KeyBindings()
+		}
+		groups = append(groups, group{p.Title(), bindings})
+	}
+
+	var cols []string
+	for i, g := range groups {
+		body := m.help.FullHelpView([][]key.Binding{g.bindings})
+		if len(g.bindings) == 0 {
+			body = style.SubtitleStyle.Render("no keys of its own")
+		}
+		col := lipgloss.JoinVertical(lipgloss.Left, style.TitleStyle.Render(g.title), "", body)
+		if i > 0 {
+			col = lipgloss.NewStyle().PaddingLeft(4).Render(col)
+		}
+		cols = append(cols, col)
+	}
+
+	closeKey := m.keys.Help.Help().Key
+	box := style.PanelActiveStyle.Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, cols...),
+		"",
+		style.SubtitleStyle.Render(closeKey+" or esc to close"),
+	))
+	return lipgloss.Place(m.width-2, max(m.height-3, 5), lipgloss.Center, lipgloss.Center, box)
+}
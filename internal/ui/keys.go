@@ -1,37 +1,154 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+)
 
 type keyMap struct {
-	Quit          key.Binding
-	Refresh       key.Binding
-	Tab           key.Binding
-	Panel1        key.Binding
-	Panel2        key.Binding
-	Panel3        key.Binding
-	Panel4        key.Binding
-	ToggleWeather key.Binding
-	ToggleCrypto  key.Binding
-	ToggleNews    key.Binding
-	ToggleGitHub  key.Binding
-	Up            key.Binding
-	Down          key.Binding
-	Open          key.Binding
+	Quit    key.Binding
+	Refresh key.Binding
+	Tab     key.Binding
+	Focus   key.Binding
+	Zoom    key.Binding
+	Help    key.Binding
+	Palette key.Binding
 }
 
+// Keys are the default global bindings. Each model works on a copy with the
+// config's overrides applied.
 var Keys = keyMap{
-	Quit:          key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-	Refresh:       key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
-	Tab:           key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "focus")),
-	Panel1:        key.NewBinding(key.WithKeys("1"), key.WithHelp("1", "weather")),
-	Panel2:        key.NewBinding(key.WithKeys("2"), key.WithHelp("2", "crypto")),
-	Panel3:        key.NewBinding(key.WithKeys("3"), key.WithHelp("3", "news")),
-	Panel4:        key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "github")),
-	ToggleWeather: key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "toggle weather")),
-	ToggleCrypto:  key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "toggle crypto")),
-	ToggleNews:    key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "toggle news")),
-	ToggleGitHub:  key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "toggle github")),
-	Up:            key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
-	Down:          key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "down")),
-	Open:          key.NewBinding(key.WithKeys("o", "enter"), key.WithHelp("o", "open")),
+	Quit:    key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+	Refresh: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh")),
+	Tab:     key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "focus")),
+	Focus:   key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "focus panel")),
+	Zoom:    key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "zoom")),
+	Help:    key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+	Palette: key.NewBinding(key.WithKeys(":", "ctrl+p"), key.WithHelp(":", "commands")),
+}
+
+// closeHelp also dismisses the help overlay.
+var closeHelp = key.NewBinding(key.WithKeys("esc"))
+
+// action is a remappable binding and its name under keys: in the config.
+type action struct {
+	name    string
+	binding *key.Binding
+}
+
+// actions lists the global bindings followed by the ones shared by panels,
+// in the order the status bar and help show them.
+func (k *keyMap) actions(nav *panel.KeyMap) []action {
+	return []action{
+		{"quit", &k.Quit},
+		{"refresh", &k.Refresh},
+		{"focus_next", &k.Tab},
+		{"focus", &k.Focus},
+		{"zoom", &k.Zoom},
+		{"help", &k.Help},
+		{"palette", &k.Palette},
+		{"up", &nav.Up},
+		{"down", &nav.Down},
+		{"page_up", &nav.PageUp},
+		{"page_down", &nav.PageDown},
+		{"top", &nav.Top},
+		{"bottom", &nav.Bottom},
+		{"filter", &nav.Filter},
+		{"next_match", &nav.NextMatch},
+		{"prev_match", &nav.PrevMatch},
+		{"open", &nav.Open},
+	}
+}
+
+// bindKeys applies the config's key overrides to the defaults and checks
+// that no key does two things. Toggle keys take part in the check, though
+// instances may share one to toggle together, and the match keys may reuse
+// a toggle key since they only act while a filter is applied. The panel bindings are
+// installed in panel.Keys, which every panel reads.
+func bindKeys(overrides map[string]config.KeyList, panels []instance) (keyMap, error) {
+	keys, nav := Keys, panel.Keys
+	actions := keys.actions(&nav)
+
+	for name, list := range overrides {
+		i := slices.IndexFunc(actions, func(a action) bool { return a.name == name })
+		if i < 0 {
+			var names []string
+			for _, a := range actions {
+				names = append(names, a.name)
+			}
+			return keys, fmt.Errorf("keys: unknown action %q (want one of %s)", name, strings.Join(names, ", "))
+		}
+		if len(list) == 0 {
+			return keys, fmt.Errorf("keys: %s has no keys", name)
+		}
+		b := actions[i].binding
+		ks := make([]string, len(list))
+		for j, k := range list {
+			ks[j] = keyName(k)
+		}
+		b.SetKeys(ks...)
+		b.SetHelp(helpKey(name, ks), b.Help().Desc)
+	}
+
+	owner := map[string]string{}
+	claim := func(k, what string) error {
+		prev, ok := owner[k]
+		if ok && prev != what && !(isToggle(prev) && isToggle(what)) && !shadows(prev, what) {
+			return fmt.Errorf("keys: %q is bound to both %s and %s", panel.KeyLabel(k), prev, what)
+		}
+		owner[k] = what
+		return nil
+	}
+	for _, a := range actions {
+		for _, k := range a.binding.Keys() {
+			if err := claim(k, a.name); err != nil {
+				return keys, err
+			}
+		}
+	}
+	for _, p := range panels {
+		for _, k := range p.toggle.Keys() {
+			if err := claim(k, "toggle "+p.id); err != nil {
+				return keys, err
+			}
+		}
+	}
+
+	panel.Keys = nav
+	return keys, nil
+}
+
+func isToggle(what string) bool {
+	return strings.HasPrefix(what, "toggle ")
+}
+
+// shadows reports whether a and b are a match key and a toggle, which can
+// share a key: the match key wins while a filter is applied.
+func shadows(a, b string) bool {
+	isMatch := func(what string) bool {
+		return what == "next_match" || what == "prev_match"
+	}
+	return isMatch(a) && isToggle(b) || isToggle(a) && isMatch(b)
+}
+
+// keyName maps the names people write in YAML to bubbletea's key strings.
+func keyName(k string) string {
+	if k == "space" {
+		return " "
+	}
+	return k
+}
+
+// helpKey is the label shown for a remapped binding: the first key, or the
+// range for focus, whose keys select panels 1, 2, 3…
+func helpKey(name string, keys []string) string {
+	if name == "focus" && len(keys) > 1 {
+		return panel.KeyLabel(keys[0]) + "-" + panel.KeyLabel(keys[len(keys)-1])
+	}
+	return panel.KeyLabel(keys[0])
 }
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"pulse/internal/config"
+	"pulse/internal/panel"
+)
+
+func TestBindKeys(t *testing.T) {
+	defaults := panel.Keys
+	defer func() { panel.Keys = defaults }()
+
+	tests := []struct {
+		name      string
+		overrides map[string]config.KeyList
+		toggles   []string // one panel per key
+		err       string
+	}{
+		{"defaults", nil, []string{"w", "c"}, ""},
+		{"conflict", map[string]config.KeyList{"refresh": {"q"}}, nil, `keys: "q" is bound to both quit and refresh`},
+		{"freed key", map[string]config.KeyList{"quit": {"Q"}, "refresh": {"q"}}, nil, ""},
+		{"toggle conflict", nil, []string{"r"}, `keys: "r" is bound to both refresh and toggle p0`},
+		{"shared toggle", nil, []string{"w", "w"}, ""},
+		{"match key on a toggle", nil, []string{"n", "N"}, ""},
+		{"top on a toggle", map[string]config.KeyList{"top": {"g"}}, []string{"g"}, `keys: "g" is bound to both top and toggle p0`},
+		{"unknown action", map[string]config.KeyList{"jump": {"j"}}, nil, `keys: unknown action "jump"`},
+		{"empty list", map[string]config.KeyList{"zoom": {}}, nil, "keys: zoom has no keys"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			panel.Keys = defaults
+			var panels []instance
+			for i, k := range tt.toggles {
+				id := "p" + string(rune('0'+i))
+				panels = append(panels, instance{id: id, toggle: key.NewBinding(key.WithKeys(k))})
+			}
+			_, err := bindKeys(tt.overrides, panels)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("bindKeys() error: %v", err)
+			case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+				t.Errorf("bindKeys() error = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBindKeysRemap(t *testing.T) {
+	defer func(k panel.KeyMap) { panel.Keys = k }(panel.Keys)
+
+	keys, err := bindKeys(map[string]config.KeyList{"zoom": {"space"}, "up": {"up", "i"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := keys.Zoom.Keys(); !slices.Equal(got, []string{" "}) || keys.Zoom.Help().Key != "space" {
+		t.Errorf("zoom keys = %q, help %q", got, keys.Zoom.Help().Key)
+	}
+	if got := panel.Keys.Up.Keys(); !slices.Equal(got, []string{"up", "i"}) || panel.Keys.Up.Help().Key != "↑" {
+		t.Errorf("up keys = %q, help %q; want them installed in panel.Keys", got, panel.Keys.Up.Help().Key)
+	}
+}
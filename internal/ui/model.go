@@ -1,36 +1,124 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/cache"
 	"pulse/internal/config"
-	"pulse/internal/panels/crypto"
-	"pulse/internal/panels/github"
-	"pulse/internal/panels/news"
-	"pulse/internal/panels/weather"
+	"pulse/internal/panel"
+	"pulse/internal/retry"
+	"pulse/internal/scheduler"
 )
 
 type clockTickMsg time.Time
 
+// panelMsg carries a message produced by a panel's command back to the
+// panel that issued it, so several panels of one type don't see each other's
+// responses.
+type panelMsg struct {
+	id  string
+	msg tea.Msg
+}
+
+// fetchedMsg carries the result of a scheduled fetch so the scheduler can
+// arm the panel's next timer before the result is routed to the panel.
+type fetchedMsg struct {
+	id  string
+	msg tea.Msg
+}
+
+// click records where and when the mouse was last clicked.
+type click struct {
+	id  string
+	row int
+	at  time.Time
+}
+
+type instance struct {
+	id      string
+	def     panel.Definition
+	panel   panel.Panel
+	visible bool
+	toggle  key.Binding
+	size    panel.SizeMsg // last size sent to the panel
+}
+
 type Model struct {
 	Config  config.Config
 	width   int
 	height  int
 	focused int
+	zoomed  bool // focused panel fills the grid area
+	keys    keyMap
+	help    help.Model
+	helpOn  bool     // ? overlay is showing
+	palette *palette // open command palette, if any
+	click   click    // last left click, to detect double-clicks
 	clock   time.Time
-	Visible [4]bool // 0=weather, 1=crypto, 2=news, 3=github
-	Weather weather.Model
-	Crypto  crypto.Model
-	News    news.Model
-	GitHub  github.Model
+	panels  []instance
+	sched   *scheduler.Scheduler
+	cache   cache.Store
 }
 
-func NewModel(cfg config.Config, visible [4]bool) Model {
+// NewModel builds the panels declared in cfg.Panels, or one of each
+// registered type when none are declared. visible maps panel types or ids to
+// their initial visibility; a nil map shows every panel.
+func NewModel(cfg config.Config, visible map[string]bool) (Model, error) {
+	built, err := panel.Build(cfg)
+	if err != nil {
+		return Model{}, err
+	}
+
+	var panels []instance
+	sched := scheduler.New()
+	store := cache.Store{Dir: panel.Or(cfg.CacheDir, cache.DefaultDir())}
+	for _, in := range built {
+		p := in.Panel
+
+		// A missing or unreadable cache just means starting empty.
+		if c, ok := p.(panel.Cacheable); ok {
+			if data, saved, err := store.Load(in.ID); err == nil {
+				if restored, err := c.Restore(data, saved); err == nil {
+					p = restored
+				}
+			}
+		}
+		sched.Add(in.ID, p.Interval(), in.Config.Retry.Merge(retry.Default))
+
+		toggle := panel.Or(in.Config.Key, in.Def.Toggle)
+		panels = append(panels, instance{
+			id:      in.ID,
+			def:     in.Def,
+			panel:   p,
+			visible: in.Shown(visible),
+			toggle:  key.NewBinding(key.WithKeys(toggle), key.WithHelp(toggle, "toggle "+in.ID)),
+		})
+	}
+
+	if cfg.Layout != nil {
+		for _, name := range cfg.Layout.Panels() {
+			if !slices.ContainsFunc(panels, func(in instance) bool {
+				return in.id == name || in.def.Name == name
+			}) {
+				return Model{}, fmt.Errorf("layout: unknown panel %q", name)
+			}
+		}
+	}
+
+	keys, err := bindKeys(cfg.Keys, panels)
+	if err != nil {
+		return Model{}, err
+	}
+
 	// Focus the first visible panel
 	focused := 0
-	for i, v := range visible {
-		if v {
+	for i, p := range panels {
+		if p.visible {
 			focused = i
 			break
 		}
@@ -38,25 +126,83 @@ func NewModel(cfg config.Config, visible [4]bool) Model {
 	return Model{
 		Config:  cfg,
 		focused: focused,
+		keys:    keys,
+		help:    newHelp(),
 		clock:   time.Now(),
-		Visible: visible,
-		Weather: weather.New(cfg),
-		Crypto:  crypto.New(cfg),
-		News:    news.New(),
-		GitHub:  github.New(cfg),
-	}
+		panels:  panels,
+		sched:   sched,
+		cache:   store,
+	}, nil
 }
 
 func (m *Model) focusNext() {
-	for i := 1; i <= 4; i++ {
-		next := (m.focused + i) % 4
-		if m.Visible[next] {
+	n := len(m.panels)
+	for i := 1; i <= n; i++ {
+		next := (m.focused + i) % n
+		if m.panels[next].visible {
 			m.focused = next
 			return
 		}
 	}
 }
 
+func (m Model) indexOf(id string) int {
+	for i, p := range m.panels {
+		if p.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// tag wraps cmd so that the message it produces is routed back to panel id.
+// Batches are unwrapped so each command inside is tagged individually.
+func tag(id string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				cmds[i] = tag(id, c)
+			}
+			return cmds
+		default:
+			return panelMsg{id: id, msg: msg}
+		}
+	}
+}
+
+// fetch starts panel i's fetch command. Callers must check with the
+// scheduler first so only one fetch per panel is ever in flight.
+func (m *Model) fetch(i int) tea.Cmd {
+	var cmd tea.Cmd
+	p := &m.panels[i]
+	p.panel, cmd = p.panel.Fetch()
+	id := p.id
+	return func() tea.Msg {
+		if cmd == nil {
+			return fetchedMsg{id: id}
+		}
+		return fetchedMsg{id: id, msg: cmd()}
+	}
+}
+
+// show starts a panel that just became visible.
+func (m *Model) show(i int) tea.Cmd {
+	p := m.panels[i]
+	fetch, wait := m.sched.Start(p.id)
+	cmds := []tea.Cmd{tag(p.id, p.panel.Init()), wait}
+	if fetch {
+		cmds = append(cmds, m.fetch(i))
+	}
+	return tea.Batch(cmds...)
+}
+
 func clockTickCmd() tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
 		return clockTickMsg(t)
@@ -65,25 +211,18 @@ func clockTickCmd() tea.Cmd {
 
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{clockTickCmd()}
-	if m.Visible[0] {
-		cmds = append(cmds, m.Weather.Init())
-	}
-	if m.Visible[1] {
-		cmds = append(cmds, m.Crypto.Init())
-	}
-	if m.Visible[2] {
-		cmds = append(cmds, m.News.Init())
-	}
-	if m.Visible[3] {
-		cmds = append(cmds, m.GitHub.Init())
+	for i, p := range m.panels {
+		if p.visible {
+			cmds = append(cmds, m.show(i))
+		}
 	}
 	return tea.Batch(cmds...)
 }
 
 func (m Model) visibleCount() int {
 	count := 0
-	for _, v := range m.Visible {
-		if v {
+	for _, p := range m.panels {
+		if p.visible {
 			count++
 		}
 	}
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"pulse/internal/panel"
+	"pulse/internal/style"
+	"pulse/internal/terminal"
+)
+
+// paletteSize is how many matches the palette lists at once.
+const paletteSize = 10
+
+// command is one command palette entry. run gets the argument typed after
+// choosing it when prompt is set.
+type command struct {
+	title  string
+	prompt string
+	run    func(m *Model, arg string) (tea.Cmd, error)
+}
+
+// palette is the state of the open command palette.
+type palette struct {
+	input    textinput.Model
+	commands []command
+	matches  []int // indexes into commands, best first
+	cursor   int
+	chosen   *command // waiting for its argument
+	err      error
+}
+
+var paletteKeys = struct {
+	Up, Down, Run, Back key.Binding
+}{
+	Up:   key.NewBinding(key.WithKeys("up", "ctrl+k")),
+	Down: key.NewBinding(key.WithKeys("down", "ctrl+j", "ctrl+n")),
+	Run:  key.NewBinding(key.WithKeys("enter")),
+	Back: key.NewBinding(key.WithKeys("esc")),
+}
+
+// openPalette collects the commands available right now and shows the
+// palette.
+func (m *Model) openPalette() {
+	in := textinput.New()
+	in.Prompt = ": "
+	in.PromptStyle = style.AccentStyle
+	in.PlaceholderStyle = style.SubtitleStyle
+	in.Placeholder = "type a command"
+	in.Cursor.SetMode(cursor.CursorStatic)
+	in.Focus()
+	p := &palette{input: in, commands: m.commands()}
+	p.filter()
+	m.palette = p
+}
+
+// commands lists the dashboard's own actions followed by those of visible
+// panels that implement panel.Actor.
+func (m Model) commands() []command {
+	var cmds []command
+	for i, p := range m.panels {
+		if p.visible {
+			cmds = append(cmds, command{
+				title: "Refresh " + p.id,
+				run:   func(m *Model, _ string) (tea.Cmd, error) { return m.refresh(i), nil },
+			})
+		}
+		verb := "Show "
+		if p.visible {
+			verb = "Hide "
+		}
+		cmds = append(cmds, command{
+			title: verb + p.id,
+			run:   func(m *Model, _ string) (tea.Cmd, error) { return m.toggle([]int{i}), nil },
+		})
+	}
+
+	if m.focused < len(m.panels) && m.panels[m.focused].visible {
+		if u := m.panels[m.focused].panel.SelectedURL(); u != "" {
+			cmds = append(cmds,
+				command{
+					title: "Open selected item",
+					run:   func(*Model, string) (tea.Cmd, error) { return openURL(u), nil },
+				},
+				command{
+					title: "Copy selected URL",
+					run:   func(*Model, string) (tea.Cmd, error) { return copyText(u), nil },
+				},
+			)
+		}
+	}
+
+	zoom := "Zoom focused panel"
+	if m.zoomed {
+		zoom = "Unzoom"
+	}
+	cmds = append(cmds, command{
+		title: zoom,
+		run: func(m *Model, _ string) (tea.Cmd, error) {
+			m.zoomed = !m.zoomed && m.focused < len(m.panels) && m.panels[m.focused].visible
+			return nil, nil
+		},
+	})
+
+	var themes []string
+	for name := range style.Themes {
+		themes = append(themes, name)
+	}
+	sort.Strings(themes)
+	for _, name := range append(themes, "auto") {
+		cmds = append(cmds, command{
+			title: "Theme: " + name,
+			run:   func(m *Model, _ string) (tea.Cmd, error) { return nil, m.setTheme(name) },
+		})
+	}
+
+	for i, p := range m.panels {
+		a, ok := p.panel.(panel.Actor)
+		if !ok || !p.visible {
+			continue
+		}
+		for _, action := range a.Actions() {
+			cmds = append(cmds, command{
+				title:  p.id + ": " + action.Name,
+				prompt: action.Prompt,
+				run: func(m *Model, arg string) (tea.Cmd, error) {
+					return m.runAction(i, action, arg)
+				},
+			})
+		}
+	}
+
+	cmds = append(cmds, command{
+		title: "Quit",
+		run:   func(*Model, string) (tea.Cmd, error) { return tea.Quit, nil },
+	})
+	return cmds
+}
+
+// runAction runs a panel's palette action on its current state and, when
+// the action asks for it, fetches with the new settings.
+func (m *Model) runAction(i int, action panel.Action, arg string) (tea.Cmd, error) {
+	p := &m.panels[i]
+	a, ok := p.panel.(panel.Actor)
+	if !ok {
+		return nil, fmt.Errorf("%s has no actions", p.id)
+	}
+	updated, err := a.RunAction(action.Name, arg)
+	if err != nil {
+		return nil, err
+	}
+	p.panel = updated
+	if action.Refresh {
+		return m.refetch(i), nil
+	}
+	return nil, nil
+}
+
+// setTheme switches the running dashboard to a built-in theme.
+func (m *Model) setTheme(name string) error {
+	theme, err := style.Resolve(style.Theme{Base: name})
+	if err != nil {
+		return err
+	}
+	style.Apply(theme)
+	m.Config.Theme = theme
+	m.help = newHelp()
+	return nil
+}
+
+// updatePalette handles a key while the palette is open.
+func (m Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	switch {
+	case key.Matches(msg, paletteKeys.Back):
+		if p.chosen != nil {
+			p.chosen = nil
+			p.err = nil
+			p.input.Reset()
+			p.input.Placeholder = "type a command"
+			p.filter()
+			return m, nil
+		}
+		m.palette = nil
+		return m, nil
+
+	case key.Matches(msg, paletteKeys.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, paletteKeys.Down):
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return m, nil
+
+	case key.Matches(msg, paletteKeys.Run):
+		c, arg := p.chosen, strings.TrimSpace(p.input.Value())
+		if c == nil {
+			if len(p.matches) == 0 {
+				return m, nil
+			}
+			c = &p.commands[p.matches[p.cursor]]
+			if c.prompt != "" {
+				p.chosen = c
+				p.err = nil
+				p.input.Reset()
+				p.input.Placeholder = c.prompt
+				return m, nil
+			}
+			arg = ""
+		} else if arg == "" {
+			p.err = fmt.Errorf("%s: enter a value", c.prompt)
+			return m, nil
+		}
+		cmd, err := c.run(&m, arg)
+		if err != nil {
+			p.err = err
+			return m, nil
+		}
+		m.palette = nil
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.chosen == nil {
+		p.filter()
+	}
+	return m, cmd
+}
+
+// filter ranks the commands against the typed text.
+func (p *palette) filter() {
+	type match struct{ index, score int }
+	var matches []match
+	for i, c := range p.commands {
+		if score, ok := fuzzyScore(p.input.Value(), c.title); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return b.score - a.score })
+	p.matches = p.matches[:0]
+	for _, mt := range matches {
+		p.matches = append(p.matches, mt.index)
+	}
+	p.cursor = 0
+	p.err = nil
+}
+
+// paletteView renders the palette near the top of the grid area.
+func (m Model) paletteView() string {
+	p := m.palette
+	width := min(60, m.width-8)
+
+	lines := []string{p.input.View(), ""}
+	if p.chosen != nil {
+		lines = append(lines, style.AccentStyle.Render("▸ "+p.chosen.title))
+	} else {
+		start := max(0, p.cursor-paletteSize+1)
+		for i := start; i < len(p.matches) && i < start+paletteSize; i++ {
+			title := p.commands[p.matches[i]].title
+			if p.commands[p.matches[i]].prompt != "" {
+				title += "…"
+			}
+			if i == p.cursor {
+				lines = append(lines, style.AccentStyle.Render("▸ "+title))
+			} else {
+				lines = append(lines, "  "+title)
+			}
+		}
+		if len(p.matches) == 0 {
+			lines = append(lines, style.SubtitleStyle.Render("  no matching command"))
+		}
+	}
+	if p.err != nil {
+		lines = append(lines, "", style.ErrorStyle.Render(p.err.Error()))
+	}
+	lines = append(lines, "", style.SubtitleStyle.Render("↑↓ select · enter run · esc back"))
+
+	box := style.PanelActiveStyle.Width(width).Padding(0, 1).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width-2, max(m.height-3, 5), lipgloss.Center, lipgloss.Top,
+		lipgloss.NewStyle().MarginTop(1).Render(box))
+}
+
+// copyText puts s on the system clipboard, falling back to the terminal's
+// OSC 52 clipboard when no clipboard tool is available (e.g. over SSH). The
+// sequence goes through the program's output so it doesn't split a frame.
+func copyText(s string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(s); err != nil {
+			termenv.NewOutput(terminal.Stdout).Copy(s)
+		}
+		return nil
+	}
+}
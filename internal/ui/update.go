@@ -3,17 +3,24 @@ package ui
 import (
 	"os/exec"
 	"runtime"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
-	"pulse/internal/panels/crypto"
-	"pulse/internal/panels/github"
-	"pulse/internal/panels/news"
-	"pulse/internal/panels/weather"
+	"pulse/internal/cache"
+	"pulse/internal/layout"
+	"pulse/internal/panel"
+	"pulse/internal/scheduler"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	m = next.(Model)
+	return m, tea.Batch(cmd, m.resize())
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -23,100 +30,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, Keys.Quit):
-			return m, tea.Quit
-		case key.Matches(msg, Keys.Tab):
-			m.focusNext()
-			return m, nil
-		case key.Matches(msg, Keys.Panel1):
-			m.focused = 0
-			return m, nil
-		case key.Matches(msg, Keys.Panel2):
-			m.focused = 1
-			return m, nil
-		case key.Matches(msg, Keys.Panel3):
-			m.focused = 2
-			return m, nil
-		case key.Matches(msg, Keys.Panel4):
-			m.focused = 3
-			return m, nil
-		case key.Matches(msg, Keys.Refresh):
-			var refreshCmds []tea.Cmd
-			if m.Visible[0] {
-				refreshCmds = append(refreshCmds, weather.FetchCmd(m.Config))
-			}
-			if m.Visible[1] {
-				refreshCmds = append(refreshCmds, crypto.FetchCmd(m.Config))
-			}
-			if m.Visible[2] {
-				refreshCmds = append(refreshCmds, news.FetchCmd())
-			}
-			if m.Visible[3] {
-				refreshCmds = append(refreshCmds, github.FetchCmd(m.Config))
-			}
-			return m, tea.Batch(refreshCmds...)
-
-		// Toggle panels
-		case key.Matches(msg, Keys.ToggleWeather):
-			if m.visibleCount() > 1 || !m.Visible[0] {
-				m.Visible[0] = !m.Visible[0]
-				if m.Visible[0] {
-					return m, m.Weather.Init()
-				}
-			}
-			return m, nil
-		case key.Matches(msg, Keys.ToggleCrypto):
-			if m.visibleCount() > 1 || !m.Visible[1] {
-				m.Visible[1] = !m.Visible[1]
-				if m.Visible[1] {
-					return m, m.Crypto.Init()
-				}
-			}
-			return m, nil
-		case key.Matches(msg, Keys.ToggleNews):
-			if m.visibleCount() > 1 || !m.Visible[2] {
-				m.Visible[2] = !m.Visible[2]
-				if m.Visible[2] {
-					return m, m.News.Init()
-				}
-			}
-			return m, nil
-		case key.Matches(msg, Keys.ToggleGitHub):
-			if m.visibleCount() > 1 || !m.Visible[3] {
-				m.Visible[3] = !m.Visible[3]
-				if m.Visible[3] {
-					return m, m.GitHub.Init()
-				}
-			}
-			return m, nil
+		return m.handleKey(msg)
 
-		// News panel navigation (when focused)
-		case m.focused == 2 && key.Matches(msg, Keys.Down):
-			m.News.SelectNext()
-			return m, nil
-		case m.focused == 2 && key.Matches(msg, Keys.Up):
-			m.News.SelectPrev()
-			return m, nil
-		case m.focused == 2 && key.Matches(msg, Keys.Open):
-			if u := m.News.SelectedURL(); u != "" {
-				return m, openURL(u)
-			}
-			return m, nil
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
 
-		// GitHub panel navigation (when focused)
-		case m.focused == 3 && key.Matches(msg, Keys.Down):
-			m.GitHub.SelectNext()
-			return m, nil
-		case m.focused == 3 && key.Matches(msg, Keys.Up):
-			m.GitHub.SelectPrev()
+	case panelMsg:
+		i := m.indexOf(msg.id)
+		_, background := msg.msg.(panel.Background)
+		if i < 0 || !m.panels[i].visible && !background {
 			return m, nil
-		case m.focused == 3 && key.Matches(msg, Keys.Open):
-			if u := m.GitHub.SelectedURL(); u != "" {
-				return m, openURL(u)
+		}
+		return m, m.updatePanel(i, msg.msg)
+
+	case scheduler.TickMsg:
+		if i := m.indexOf(msg.ID); i >= 0 && m.sched.Tick(msg) {
+			return m, m.fetch(i)
+		}
+		return m, nil
+
+	case fetchedMsg:
+		var err error
+		var notBefore time.Time
+		if r, ok := msg.msg.(panel.Result); ok {
+			err = r.FetchError()
+		}
+		if t, ok := msg.msg.(panel.Throttled); ok {
+			notBefore = t.NotBefore()
+		}
+		cmds = append(cmds, m.sched.Done(msg.id, err, notBefore))
+		if i := m.indexOf(msg.id); i >= 0 && msg.msg != nil {
+			cmds = append(cmds, m.updatePanel(i, msg.msg))
+			if c, ok := m.panels[i].panel.(panel.Cacheable); ok && err == nil {
+				cmds = append(cmds, saveCmd(m.cache, msg.id, c.Snapshot()))
 			}
-			return m, nil
 		}
+		return m, tea.Batch(cmds...)
 
 	case clockTickMsg:
 		m.clock = time.Time(msg)
@@ -124,26 +73,242 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// Route all other messages to visible sub-panels
+	for i, p := range m.panels {
+		if p.visible {
+			cmds = append(cmds, m.updatePanel(i, msg))
+		}
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.palette != nil {
+		return m.updatePalette(msg)
+	}
+	if m.helpOn {
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Help, closeHelp):
+			m.helpOn = false
+		}
+		return m, nil
+	}
+
+	// A filter being typed and n/N while one is applied take keys before the
+	// dashboard does.
+	if m.focused < len(m.panels) && m.panels[m.focused].visible {
+		focused := &m.panels[m.focused]
+		if f, ok := focused.panel.(panel.Filterer); ok && f.Captures(msg) {
+			var cmd tea.Cmd
+			focused.panel, cmd = focused.panel.HandleKey(msg)
+			return m, tag(focused.id, cmd)
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Tab):
+		m.focusNext()
+		return m, nil
+	case key.Matches(msg, m.keys.Focus):
+		if i := slices.Index(m.keys.Focus.Keys(), msg.String()); i < len(m.panels) {
+			m.focused = i
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Help):
+		m.helpOn = true
+		return m, nil
+	case key.Matches(msg, m.keys.Zoom):
+		m.zoomed = !m.zoomed && m.focused < len(m.panels) && m.panels[m.focused].visible
+		return m, nil
+	case key.Matches(msg, m.keys.Refresh):
+		var refreshCmds []tea.Cmd
+		for i, p := range m.panels {
+			if p.visible {
+				refreshCmds = append(refreshCmds, m.refresh(i))
+			}
+		}
+		return m, tea.Batch(refreshCmds...)
+	case key.Matches(msg, m.keys.Palette):
+		m.openPalette()
+		return m, nil
+	}
+
+	// Toggle panels; instances sharing a key toggle together
+	var toggled []int
+	for i, p := range m.panels {
+		if key.Matches(msg, p.toggle) {
+			toggled = append(toggled, i)
+		}
+	}
+	if len(toggled) > 0 {
+		return m, m.toggle(toggled)
+	}
+
+	if m.focused >= len(m.panels) || !m.panels[m.focused].visible {
+		return m, nil
+	}
+	focused := &m.panels[m.focused]
+
+	if key.Matches(msg, panel.Keys.Open) {
+		if u := focused.panel.SelectedURL(); u != "" {
+			return m, openURL(u)
+		}
+		return m, nil
+	}
+
 	var cmd tea.Cmd
+	focused.panel, cmd = focused.panel.HandleKey(msg)
+	return m, tag(focused.id, cmd)
+}
+
+// doubleClick is the longest gap between two clicks on one row that still
+// opens the item.
+const doubleClick = 400 * time.Millisecond
+
+// handleMouse focuses the panel under a click, selects the item under it
+// and opens the item on a double-click. The wheel moves the selection of
+// the panel under the pointer.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.palette != nil || m.helpOn || m.visibleCount() == 0 || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	box, ok := m.layout().At(msg.X, msg.Y)
+	if !ok {
+		return m, nil
+	}
+	i := m.indexOf(box.Panel)
+	p := &m.panels[i]
+	s, selectable := p.panel.(panel.Selectable)
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		if selectable {
+			p.panel = s.Scroll(-1)
+		}
+	case tea.MouseButtonWheelDown:
+		if selectable {
+			p.panel = s.Scroll(1)
+		}
+	case tea.MouseButtonLeft:
+		m.focused = i
+		if !selectable {
+			return m, nil
+		}
+		_, height := contentSize(box)
+		row := msg.Y - box.Y - 1 // below the top border
+		var hit bool
+		if p.panel, hit = s.SelectAt(row, height); !hit {
+			return m, nil
+		}
+		last := m.click
+		m.click = click{id: p.id, row: row, at: time.Now()}
+		if last.id == p.id && last.row == row && m.click.at.Sub(last.at) < doubleClick {
+			m.click = click{}
+			if u := p.panel.SelectedURL(); u != "" {
+				return m, openURL(u)
+			}
+		}
+	}
+	return m, nil
+}
 
-	if m.Visible[0] {
-		m.Weather, cmd = m.Weather.Update(msg)
-		cmds = append(cmds, cmd)
+// toggle shows the given panels, or hides them if the first is visible.
+// Hiding is refused when it would leave no panel on screen.
+func (m *Model) toggle(indexes []int) tea.Cmd {
+	show := !m.panels[indexes[0]].visible
+	if !show {
+		others := 0
+		for i, p := range m.panels {
+			if p.visible && !slices.Contains(indexes, i) {
+				others++
+			}
+		}
+		if others == 0 {
+			return nil
+		}
 	}
-	if m.Visible[1] {
-		m.Crypto, cmd = m.Crypto.Update(msg)
-		cmds = append(cmds, cmd)
+	var cmds []tea.Cmd
+	for _, i := range indexes {
+		p := &m.panels[i]
+		switch {
+		case show && !p.visible:
+			p.visible = true
+			cmds = append(cmds, m.show(i))
+		case !show && p.visible:
+			p.visible = false
+			m.sched.Stop(p.id)
+		}
 	}
-	if m.Visible[2] {
-		m.News, cmd = m.News.Update(msg)
-		cmds = append(cmds, cmd)
+	// Hiding the zoomed panel ends the zoom, so the next toggle doesn't
+	// bring it back unexpectedly.
+	if !m.panels[m.focused].visible {
+		m.zoomed = false
 	}
-	if m.Visible[3] {
-		m.GitHub, cmd = m.GitHub.Update(msg)
-		cmds = append(cmds, cmd)
+	return tea.Batch(cmds...)
+}
+
+// refresh fetches panel i now unless the scheduler coalesces or defers it.
+func (m *Model) refresh(i int) tea.Cmd {
+	if m.sched.Trigger(m.panels[i].id) {
+		return m.fetch(i)
 	}
+	return nil
+}
 
-	return m, tea.Batch(cmds...)
+// refetch fetches panel i now, or right after the fetch in flight, which
+// started with settings that have since changed.
+func (m *Model) refetch(i int) tea.Cmd {
+	if m.sched.Refetch(m.panels[i].id) {
+		return m.fetch(i)
+	}
+	return nil
+}
+
+// resize tells each visible panel its content size when the layout gave it
+// a new one, so list panels can keep their selection scrolled into view.
+func (m *Model) resize() tea.Cmd {
+	if m.width == 0 || m.visibleCount() == 0 {
+		return nil
+	}
+	var cmds []tea.Cmd
+	var walk func(b layout.Box)
+	walk = func(b layout.Box) {
+		for _, c := range b.Children {
+			walk(c)
+		}
+		if b.Panel == "" {
+			return
+		}
+		i := m.indexOf(b.Panel)
+		width, height := contentSize(b)
+		if size := (panel.SizeMsg{Width: width, Height: height}); size != m.panels[i].size {
+			m.panels[i].size = size
+			cmds = append(cmds, m.updatePanel(i, size))
+		}
+	}
+	walk(m.layout())
+	return tea.Batch(cmds...)
+}
+
+// updatePanel passes msg to panel i and tags the resulting command.
+func (m *Model) updatePanel(i int, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	p := &m.panels[i]
+	p.panel, cmd = p.panel.Update(msg)
+	return tag(p.id, cmd)
+}
+
+// saveCmd writes a panel's payload to the cache in the background. Failing
+// to cache is not worth interrupting the dashboard for.
+func saveCmd(store cache.Store, id string, v any) tea.Cmd {
+	return func() tea.Msg {
+		store.Save(id, v)
+		return nil
+	}
 }
 
 func openURL(url string) tea.Cmd {
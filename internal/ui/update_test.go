@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"pulse/internal/panel"
+	"pulse/internal/scheduler"
+)
+
+// stub is a panel that records the messages it is updated with.
+type stub struct {
+	got []tea.Msg
+}
+
+func (s stub) Init() tea.Cmd                               { return nil }
+func (s stub) Fetch() (panel.Panel, tea.Cmd)               { return s, nil }
+func (s stub) Interval() time.Duration                     { return time.Minute }
+func (s stub) Health() panel.Health                        { return panel.Health{} }
+func (s stub) View(int, int) string                        { return "" }
+func (s stub) Title() string                               { return "stub" }
+func (s stub) HandleKey(tea.KeyMsg) (panel.Panel, tea.Cmd) { return s, nil }
+func (s stub) SelectedURL() string                         { return "" }
+
+func (s stub) Update(msg tea.Msg) (panel.Panel, tea.Cmd) {
+	s.got = append(s.got, msg)
+	return s, nil
+}
+
+type plainMsg struct{}
+
+type backgroundMsg struct{}
+
+func (backgroundMsg) Background() {}
+
+func TestHiddenPanelMessages(t *testing.T) {
+	m := Model{sched: scheduler.New(), panels: []instance{
+		{id: "a", panel: stub{}, visible: true},
+		{id: "b", panel: stub{}},
+	}}
+
+	for _, msg := range []tea.Msg{plainMsg{}, backgroundMsg{}} {
+		next, _ := m.update(panelMsg{id: "b", msg: msg})
+		m = next.(Model)
+	}
+	got := m.panels[1].panel.(stub).got
+	if len(got) != 1 || got[0] != (backgroundMsg{}) {
+		t.Errorf("hidden panel got %v, want only the background message", got)
+	}
+}
@@ -2,17 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
+	"pulse/internal/layout"
+	"pulse/internal/panel"
 	"pulse/internal/style"
 )
 
-type panelEntry struct {
-	index   int
-	content string
-}
-
 func (m Model) View() string {
 	if m.width == 0 {
 		return "  Loading..."
@@ -29,33 +28,39 @@ func (m Model) View() string {
 		fmt.Sprintf("%s%s%s", title, strings.Repeat(" ", gap), clock),
 	)
 
+	var toggles []string
+	for _, p := range m.panels {
+		if k := p.toggle.Help().Key; !slices.Contains(toggles, k) {
+			toggles = append(toggles, k)
+		}
+	}
+	toggleKeys := strings.Join(toggles, "/")
+
 	// Collect visible panels
 	count := m.visibleCount()
 	if count == 0 {
 		statusBar := style.StatusBarStyle.Render(
-			"q quit  ·  w/c/n/g toggle panels",
+			fmt.Sprintf("%s  ·  %s toggle panels", m.help.ShortHelpView([]key.Binding{m.keys.Quit}), toggleKeys),
 		)
-		return lipgloss.JoinVertical(lipgloss.Left, header, "\n  No panels visible. Press w/c/n/g to toggle.", statusBar)
+		return lipgloss.JoinVertical(lipgloss.Left, header,
+			fmt.Sprintf("\n  No panels visible. Press %s to toggle.", toggleKeys), statusBar)
 	}
 
-	// Calculate dimensions based on layout
 	var grid string
-	switch count {
-	case 1:
-		grid = m.layoutOne()
-	case 2:
-		grid = m.layoutTwo()
-	case 3:
-		grid = m.layoutThree()
+	switch {
+	case m.palette != nil:
+		grid = m.paletteView()
+	case m.helpOn:
+		grid = m.helpView()
 	default:
-		grid = m.layoutFour()
+		grid = m.renderBox(m.layout())
 	}
 
 	// Status bar — show toggle indicators
 	var indicators []string
-	labels := []string{"w:weather", "c:crypto", "n:news", "g:github"}
-	for i, label := range labels {
-		if m.Visible[i] {
+	for _, p := range m.panels {
+		label := fmt.Sprintf("%s:%s", p.toggle.Help().Key, p.id)
+		if p.visible {
 			indicators = append(indicators, style.AccentStyle.Render(label))
 		} else {
 			indicators = append(indicators, style.SubtitleStyle.Render(label))
@@ -63,112 +68,132 @@ func (m Model) View() string {
 	}
 
 	statusBar := style.StatusBarStyle.Render(
-		fmt.Sprintf("q quit  ·  r refresh  ·  tab focus  ·  %s", strings.Join(indicators, "  ")),
+		fmt.Sprintf("%s  ·  %s  ·  %s",
+			m.help.ShortHelpView(m.shortHelp()), strings.Join(indicators, "  "), m.healthSummary()),
 	)
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, grid, statusBar)
 }
 
-func (m Model) layoutOne() string {
-	pw := m.width - 2
-	ph := m.height - 3
-	if ph < 5 {
-		ph = 5
+// healthSummary counts visible panels that are failing or showing stale
+// data, e.g. "1 failing · 2 stale".
+func (m Model) healthSummary() string {
+	var failing, stale []string
+	for _, p := range m.panels {
+		if !p.visible {
+			continue
+		}
+		switch p.panel.Health().State() {
+		case panel.Failing:
+			failing = append(failing, p.id)
+		case panel.Stale:
+			stale = append(stale, p.id)
+		}
 	}
-	cw := pw - 4
-	ch := ph - 3
 
-	panels := m.getVisiblePanels(cw, ch)
-	return m.renderPanel(panels[0].index, panels[0].content, pw, ph)
-}
-
-func (m Model) layoutTwo() string {
-	pw := m.width/2 - 1
-	ph := m.height - 3
-	if ph < 5 {
-		ph = 5
+	var parts []string
+	if len(failing) > 0 {
+		parts = append(parts, style.ErrorStyle.Render(fmt.Sprintf("%d failing", len(failing))))
 	}
-	cw := pw - 4
-	ch := ph - 3
-
-	panels := m.getVisiblePanels(cw, ch)
-	left := m.renderPanel(panels[0].index, panels[0].content, pw, ph)
-	right := m.renderPanel(panels[1].index, panels[1].content, pw, ph)
-	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
-}
-
-func (m Model) layoutThree() string {
-	fullW := m.width - 2
-	halfW := m.width/2 - 1
-	halfH := (m.height - 3) / 2
-	if halfH < 5 {
-		halfH = 5
+	if len(stale) > 0 {
+		parts = append(parts, style.WarningStyle.Render(fmt.Sprintf("%d stale", len(stale))))
 	}
-
-	panels := m.getVisiblePanels(halfW-4, halfH-3)
-
-	top := m.renderPanel(panels[0].index, panels[0].content, fullW, halfH)
-	bl := m.renderPanel(panels[1].index, panels[1].content, halfW, halfH)
-	br := m.renderPanel(panels[2].index, panels[2].content, halfW, halfH)
-	bottom := lipgloss.JoinHorizontal(lipgloss.Top, bl, br)
-	return lipgloss.JoinVertical(lipgloss.Left, top, bottom)
+	if len(parts) == 0 {
+		return style.PositiveStyle.Render("all fresh")
+	}
+	return strings.Join(parts, " · ")
 }
 
-func (m Model) layoutFour() string {
-	pw := m.width/2 - 1
-	ph := (m.height - 3) / 2
-	if ph < 5 {
-		ph = 5
+// layout resolves the configured layout, or the automatic one, against the
+// visible panels and solves it for the area between header and status bar.
+// Visible panels the configured layout doesn't place get an automatic grid
+// below it. While zoomed, the focused panel takes the whole area.
+func (m Model) layout() layout.Box {
+	area := layout.Rect{Y: 1, Width: m.width - 2, Height: max(m.height-3, 5)}
+	if m.zoomed && m.panels[m.focused].visible {
+		return layout.Solve(layout.Node{Panel: m.panels[m.focused].id}, area)
 	}
-	cw := pw - 4
-	ch := ph - 3
-	if cw < 10 {
-		cw = 10
+
+	var visible []string
+	for _, p := range m.panels {
+		if p.visible {
+			visible = append(visible, p.id)
+		}
 	}
-	if ch < 3 {
-		ch = 3
+	if m.Config.Layout == nil {
+		return layout.Solve(layout.Auto(visible), area)
 	}
 
-	panels := m.getVisiblePanels(cw, ch)
-
-	tl := m.renderPanel(panels[0].index, panels[0].content, pw, ph)
-	tr := m.renderPanel(panels[1].index, panels[1].content, pw, ph)
-	bl := m.renderPanel(panels[2].index, panels[2].content, pw, ph)
-	br := m.renderPanel(panels[3].index, panels[3].content, pw, ph)
+	// A leaf names a panel id or, failing that, the next unplaced panel
+	// of that type.
+	placed := map[string]bool{}
+	resolve := func(name string) (string, bool) {
+		for _, byType := range []bool{false, true} {
+			for _, p := range m.panels {
+				match := p.id == name
+				if byType {
+					match = p.def.Name == name
+				}
+				if match && p.visible && !placed[p.id] {
+					placed[p.id] = true
+					return p.id, true
+				}
+			}
+		}
+		return "", false
+	}
+	root, ok := m.Config.Layout.Prune(resolve)
 
-	top := lipgloss.JoinHorizontal(lipgloss.Top, tl, tr)
-	bottom := lipgloss.JoinHorizontal(lipgloss.Top, bl, br)
-	return lipgloss.JoinVertical(lipgloss.Left, top, bottom)
+	var extras []string
+	for _, id := range visible {
+		if !placed[id] {
+			extras = append(extras, id)
+		}
+	}
+	switch {
+	case !ok:
+		root = layout.Auto(extras)
+	case len(extras) > 0:
+		root.Weight, root.Size = len(visible)-len(extras), 0
+		extra := layout.Auto(extras)
+		extra.Weight = len(extras)
+		root = layout.Node{Split: layout.Vertical, Children: []layout.Node{root, extra}}
+	}
+	return layout.Solve(root, area)
 }
 
-func (m Model) getVisiblePanels(contentWidth, contentHeight int) []panelEntry {
-	var panels []panelEntry
-
-	type panelDef struct {
-		index int
-		view  func(int, int) string
+// renderBox draws a solved layout: each leaf as a bordered panel sized to
+// its box, splits by joining their children.
+func (m Model) renderBox(b layout.Box) string {
+	if b.Panel != "" {
+		i := m.indexOf(b.Panel)
+		content := m.panels[i].panel.View(contentSize(b))
+		return m.renderPanel(i, content, b.Width, max(b.Height, 3))
 	}
 
-	all := []panelDef{
-		{0, m.Weather.View},
-		{1, m.Crypto.View},
-		{2, m.News.View},
-		{3, m.GitHub.View},
+	var parts []string
+	for _, c := range b.Children {
+		parts = append(parts, m.renderBox(c))
 	}
-
-	for _, p := range all {
-		if m.Visible[p.index] {
-			panels = append(panels, panelEntry{
-				index:   p.index,
-				content: p.view(contentWidth, contentHeight),
-			})
-		}
+	if b.Split == layout.Horizontal {
+		return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
 	}
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
 
-	return panels
+// contentSize is the width and height a panel's View gets inside box b,
+// after borders and padding.
+func contentSize(b layout.Box) (int, int) {
+	return max(b.Width-4, 10), max(b.Height-3, 1)
 }
 
+// renderPanel draws content in a bordered box of exactly width x height,
+// cutting off lines that don't fit so small boxes can't push the rest of the
+// layout out of place.
 func (m Model) renderPanel(index int, content string, width, height int) string {
+	if lines := strings.Split(content, "\n"); len(lines) > height-2 {
+		content = strings.Join(lines[:height-2], "\n")
+	}
 	s := style.PanelStyle
 	if m.focused == index {
 		s = style.PanelActiveStyle
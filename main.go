@@ -4,35 +4,91 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"pulse/internal/config"
+	"pulse/internal/panel"
+	_ "pulse/internal/panels"
+	"pulse/internal/snapshot"
+	"pulse/internal/style"
+	"pulse/internal/terminal"
 	"pulse/internal/ui"
 )
 
 func main() {
-	showWeather := flag.Bool("weather", false, "show weather panel")
-	showCrypto := flag.Bool("crypto", false, "show crypto panel")
-	showNews := flag.Bool("news", false, "show news panel")
-	showGitHub := flag.Bool("github", false, "show github panel")
+	configPath := flag.String("config", "", "path to config file (default "+config.DefaultPath()+")")
+	once := flag.Bool("once", false, "fetch every panel once, print a snapshot and exit")
+	format := flag.String("format", "text", "snapshot format for --once: "+strings.Join(snapshot.Formats, ", "))
+	show := map[string]*bool{}
+	for _, d := range panel.Definitions() {
+		show[d.Name] = flag.Bool(d.Name, false, fmt.Sprintf("show %s panels", d.Name))
+	}
 	flag.Parse()
 
-	cfg := config.Load()
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	style.Apply(cfg.Theme)
 
 	// If no flags specified, show all panels
-	anySet := *showWeather || *showCrypto || *showNews || *showGitHub
-	visible := [4]bool{
-		!anySet || *showWeather,
-		!anySet || *showCrypto,
-		!anySet || *showNews,
-		!anySet || *showGitHub,
+	var visible map[string]bool
+	for name, set := range show {
+		if *set {
+			if visible == nil {
+				visible = map[string]bool{}
+			}
+			visible[name] = true
+		}
+	}
+
+	if *once {
+		os.Exit(runOnce(cfg, visible, *format))
 	}
 
-	m := ui.NewModel(cfg, visible)
+	m, err := ui.NewModel(cfg, visible)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithOutput(terminal.Stdout)}
+	if cfg.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, opts...)
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runOnce prints a headless snapshot and returns the process exit code:
+// 1 if any provider failed, 2 for usage or config errors.
+func runOnce(cfg config.Config, visible map[string]bool, format string) int {
+	if !slices.Contains(snapshot.Formats, format) {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (want %s)\n", format, strings.Join(snapshot.Formats, ", "))
+		return 2
+	}
+
+	built, err := panel.Build(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	var selected []panel.Instance
+	for _, in := range built {
+		if in.Shown(visible) {
+			selected = append(selected, in)
+		}
+	}
+
+	if err := snapshot.Run(os.Stdout, selected, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}